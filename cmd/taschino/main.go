@@ -0,0 +1,128 @@
+// Command taschino checks if a newer release of a GitHub repository is
+// available.
+//
+// Usage:
+//
+//	taschino -owner marco-m -repo taschino -current v0.1.0
+//
+// Exit status: 0 if up to date, 10 if an update is available, 1 on error and
+// 2 on usage error. If set, the environment variable GITHUB_TOKEN (or
+// GH_TOKEN) is used to authenticate to the GitHub API.
+//
+// With -json, the result is printed to stdout as a single JSON object, and
+// nothing else is printed to stdout. The exit status does not change. The
+// schema is:
+//
+//	{
+//	  "current": "v1.2.3",        // the -current flag
+//	  "latest": "v1.3.0",         // tag of the latest release; omitted on error
+//	  "update_available": true,   // false on error
+//	  "not_comparable": true,     // only if latest is not a valid semver
+//	  "error": "..."              // only on error
+//	}
+//
+// If the latest tag is not a valid semver, it cannot be compared with the
+// installed version: taschino reports it, if different, and exits with 0.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/marco-m/taschino/pkg/release"
+)
+
+// Exit status.
+const (
+	exitUpToDate        = 0
+	exitError           = 1
+	exitUsage           = 2
+	exitUpdateAvailable = 10
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("taschino", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	owner := flags.String("owner", "", "owner of the GitHub repository")
+	repo := flags.String("repo", "", "name of the GitHub repository")
+	current := flags.String("current", "", "installed version")
+	jsonOut := flags.Bool("json", false, "print the result as JSON")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *owner == "" || *repo == "" || *current == "" {
+		fmt.Fprintln(stderr, "taschino: -owner, -repo and -current are required")
+		flags.Usage()
+		return exitUsage
+	}
+
+	res := check(*owner, *repo, *current)
+	if *jsonOut {
+		enc := json.NewEncoder(stdout)
+		if err := enc.Encode(res); err != nil {
+			fmt.Fprintln(stderr, "taschino:", err)
+			return exitError
+		}
+	} else {
+		res.print(stdout, stderr, *owner, *repo)
+	}
+	switch {
+	case res.Error != "":
+		return exitError
+	case res.UpdateAvailable:
+		return exitUpdateAvailable
+	}
+	return exitUpToDate
+}
+
+// result is the result of the check. Its JSON encoding is the output of
+// -json: keep it stable.
+type result struct {
+	Current         string `json:"current"`
+	Latest          string `json:"latest,omitempty"`
+	UpdateAvailable bool   `json:"update_available"`
+	NotComparable   bool   `json:"not_comparable,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+func check(owner string, repo string, current string) result {
+	res := result{Current: current}
+	latest, err := release.GitHubLatest(owner, repo)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	cmp, comparable, err := release.TryCompare(current, latest)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Latest = latest
+	res.UpdateAvailable = cmp < 0
+	res.NotComparable = !comparable
+	return res
+}
+
+func (res result) print(stdout io.Writer, stderr io.Writer, owner string,
+	repo string) {
+	switch {
+	case res.Error != "":
+		fmt.Fprintln(stderr, "taschino:", res.Error)
+	case res.UpdateAvailable:
+		fmt.Fprintf(stdout, "%s/%s: update available: %s (installed %s)\n",
+			owner, repo, res.Latest, res.Current)
+	case res.NotComparable && res.Latest != res.Current:
+		fmt.Fprintf(stdout, "%s/%s: a different release is tagged: %s "+
+			"(installed %s)\n", owner, repo, res.Latest, res.Current)
+	default:
+		fmt.Fprintf(stdout, "%s/%s: up to date (installed %s, latest %s)\n",
+			owner, repo, res.Current, res.Latest)
+	}
+}
@@ -0,0 +1,259 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// osAliases are the names commonly used in asset names for each GOOS.
+var osAliases = map[string][]string{
+	"darwin":  {"darwin", "macos", "osx", "apple"},
+	"windows": {"windows", "win", "win64", "win32"},
+	"linux":   {"linux"},
+	"freebsd": {"freebsd"},
+	"openbsd": {"openbsd"},
+	"netbsd":  {"netbsd"},
+}
+
+// archAliases are the names commonly used in asset names for each GOARCH.
+var archAliases = map[string][]string{
+	"amd64": {"amd64", "x86_64", "x86-64", "x64", "64bit"},
+	"arm64": {"arm64", "aarch64"},
+	"386":   {"386", "i386", "i686", "x86", "32bit"},
+	"arm":   {"arm", "armv6", "armv7", "armhf"},
+}
+
+// universalAliases are the names of darwin assets for all architectures.
+var universalAliases = []string{"universal", "all"}
+
+// excludedAssetSuffixes are the suffixes, in lower case, of the names of the
+// assets that SelectAsset never selects, because they accompany the binaries
+// instead of being one, such as "tool_linux_amd64.tar.gz.sha256".
+var excludedAssetSuffixes = []string{
+	".asc", ".sig", ".txt", ".sha256", ".sha512", ".md5", ".pem", ".sbom",
+}
+
+// excludedContentTypes are the content types of the assets that SelectAsset
+// never selects.
+var excludedContentTypes = []string{
+	"text/plain", "application/pgp-signature", "application/json",
+}
+
+// AssetExclusions are the assets that SelectAssetExcluding never selects,
+// because they accompany the binaries instead of being one.
+type AssetExclusions struct {
+	// Suffixes are the suffixes of the excluded names, such as ".sha256",
+	// compared ignoring case.
+	Suffixes []string
+	// ContentTypes are the excluded content types, such as "text/plain",
+	// compared ignoring case and parameters such as charset.
+	ContentTypes []string
+}
+
+// DefaultAssetExclusions returns the exclusions of SelectAsset: checksums,
+// signatures, certificates, SBOMs and text files. The result is a copy, which
+// the caller can extend or trim, for example to also skip Debian packages:
+//
+//	ex := release.DefaultAssetExclusions()
+//	ex.Suffixes = append(ex.Suffixes, ".deb")
+func DefaultAssetExclusions() AssetExclusions {
+	return AssetExclusions{
+		Suffixes:     append([]string(nil), excludedAssetSuffixes...),
+		ContentTypes: append([]string(nil), excludedContentTypes...),
+	}
+}
+
+// binaryContentTypes are the content types of archives and executables, which
+// SelectAsset prefers when more than one asset matches.
+var binaryContentTypes = []string{
+	"application/octet-stream", "application/gzip", "application/x-gzip",
+	"application/zip", "application/x-zip-compressed", "application/x-tar",
+	"application/x-xz", "application/x-bzip2", "application/zstd",
+	"application/x-executable", "application/x-msdownload",
+	"application/x-apple-diskimage",
+}
+
+// SelectAsset returns the only asset of release for the platform goos/goarch
+// (as runtime.GOOS and runtime.GOARCH), using common naming conventions:
+// for example, for linux/amd64 it matches "tool_linux_amd64.tar.gz" and
+// "tool-Linux-x86_64.zip". If no asset matches, the error wraps ErrNoAsset; if
+// more than one asset matches, the error wraps ErrAmbiguousAsset.
+//
+// It skips checksums, signatures and similar assets, by their name or content
+// type (see DefaultAssetExclusions). If more than one asset matches, it
+// selects the only one, if any, with the content type of an archive or of an
+// executable, such as application/octet-stream. To select such an asset
+// explicitly, use SelectAssetFunc or Release.Asset.
+func SelectAsset(release *Release, goos string, goarch string) (*Asset, error) {
+	return SelectAssetExcluding(release, goos, goarch, DefaultAssetExclusions())
+}
+
+// SelectAssetExcluding is like SelectAsset, but skips the assets of
+// exclusions instead of the default ones. The zero AssetExclusions skips
+// nothing.
+func SelectAssetExcluding(release *Release, goos string, goarch string,
+	exclusions AssetExclusions) (*Asset, error) {
+	platform := PlatformMatcher(goos, goarch)
+	match := func(a Asset) bool {
+		return platform(a) && !exclusions.excludes(a)
+	}
+	asset, err := SelectAssetFunc(release, match)
+	if errors.Is(err, ErrAmbiguousAsset) {
+		preferred, prefErr := SelectAssetFunc(release, func(a Asset) bool {
+			return match(a) && hasContentType(a, binaryContentTypes)
+		})
+		if prefErr == nil {
+			return preferred, nil
+		}
+	}
+	return asset, err
+}
+
+// selectAsset is SelectAsset, with the exclusions of WithAssetExclusions.
+func (cfg *config) selectAsset(release *Release, goos string,
+	goarch string) (*Asset, error) {
+	if cfg.assetExclusions == nil {
+		return SelectAsset(release, goos, goarch)
+	}
+	return SelectAssetExcluding(release, goos, goarch, *cfg.assetExclusions)
+}
+
+// excludes reports whether a is among the exclusions.
+func (ex AssetExclusions) excludes(a Asset) bool {
+	name := strings.ToLower(a.Name)
+	for _, suffix := range ex.Suffixes {
+		if strings.HasSuffix(name, strings.ToLower(suffix)) {
+			return true
+		}
+	}
+	return hasContentType(a, ex.ContentTypes)
+}
+
+// hasContentType reports whether the content type of a, ignoring parameters
+// such as charset, is one of types.
+func hasContentType(a Asset, types []string) bool {
+	contentType := strings.ToLower(a.ContentType)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	contentType = strings.TrimSpace(contentType)
+	for _, t := range types {
+		if contentType == strings.ToLower(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectAssetFunc is like SelectAsset, but selects the asset with match, for
+// projects with non-standard naming.
+func SelectAssetFunc(release *Release, match func(Asset) bool) (*Asset, error) {
+	var matches []*Asset
+	for i := range release.Assets {
+		if match(release.Assets[i]) {
+			matches = append(matches, &release.Assets[i])
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: release %s has no asset for this platform",
+			ErrNoAsset, release.TagName)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = m.Name
+	}
+	return nil, fmt.Errorf("%w: release %s: %s", ErrAmbiguousAsset,
+		release.TagName, strings.Join(names, ", "))
+}
+
+// SelectAssetByTemplate returns the asset of release named as template, for
+// projects with a strict naming, such as "tool_{version}_{os}_{arch}.tar.gz".
+// The placeholders are replaced by the tag of release, with or without the
+// leading "v", and by goos and goarch or their aliases known to SelectAsset,
+// such as "x86_64" for amd64; names are compared ignoring case. If more than
+// one expansion matches, the first in the order of the aliases wins, goos and
+// goarch themselves first. If none matches, the error wraps ErrNoAsset and
+// lists the names of the assets.
+func SelectAssetByTemplate(release *Release, template string, goos string,
+	goarch string) (*Asset, error) {
+	versions := []string{release.TagName}
+	if v := strings.TrimPrefix(release.TagName, "v"); v != release.TagName {
+		versions = append(versions, v)
+	}
+	archNames := aliases(archAliases, goarch)
+	if goos == "darwin" && (goarch == "amd64" || goarch == "arm64") {
+		archNames = append(archNames[:len(archNames):len(archNames)],
+			universalAliases...)
+	}
+	var expected string
+	for _, version := range versions {
+		for _, osName := range aliases(osAliases, goos) {
+			for _, arch := range archNames {
+				name := strings.NewReplacer("{version}", version, "{os}", osName,
+					"{arch}", arch).Replace(template)
+				if expected == "" {
+					expected = name
+				}
+				for i := range release.Assets {
+					if strings.EqualFold(release.Assets[i].Name, name) {
+						return &release.Assets[i], nil
+					}
+				}
+			}
+		}
+	}
+	names := make([]string, len(release.Assets))
+	for i, a := range release.Assets {
+		names[i] = a.Name
+	}
+	return nil, fmt.Errorf("%w: release %s has no asset named as %s, such as %s "+
+		"(assets: %s)", ErrNoAsset, release.TagName, template, expected,
+		strings.Join(names, ", "))
+}
+
+// PlatformMatcher returns the matcher used by SelectAsset, for use with
+// SelectAssetFunc, for example to combine it with other conditions.
+func PlatformMatcher(goos string, goarch string) func(Asset) bool {
+	osNames := aliases(osAliases, goos)
+	archNames := aliases(archAliases, goarch)
+	if goos == "darwin" && (goarch == "amd64" || goarch == "arm64") {
+		archNames = append(archNames, universalAliases...)
+	}
+	return func(a Asset) bool {
+		name := strings.ToLower(a.Name)
+		if !containsWord(name, osNames) || !containsWord(name, archNames) {
+			return false
+		}
+		// "x86" would also match "x86_64".
+		if goarch == "386" && containsWord(name, archAliases["amd64"]) {
+			return false
+		}
+		return true
+	}
+}
+
+// aliases returns the aliases of key in table, or key itself if unknown.
+func aliases(table map[string][]string, key string) []string {
+	if names, ok := table[key]; ok {
+		return names
+	}
+	return []string{key}
+}
+
+// containsWord reports whether s contains one of words, delimited by
+// non-alphanumeric characters or by the ends of s.
+func containsWord(s string, words []string) bool {
+	for _, w := range words {
+		re := regexp.MustCompile(`(^|[^a-z0-9])` + regexp.QuoteMeta(w) +
+			`($|[^a-z0-9])`)
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
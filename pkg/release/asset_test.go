@@ -0,0 +1,114 @@
+package release_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marco-m/taschino/pkg/release"
+	"github.com/marco-m/taschino/pkg/release/releasetest"
+)
+
+func TestSelectAssetSkipsExcluded(t *testing.T) {
+	r := &release.Release{TagName: "v1.2.3", Assets: []release.Asset{
+		{Name: "tool_linux_amd64.tar.gz"},
+		{Name: "tool_linux_amd64.tar.gz.SHA256"},
+		{Name: "tool_linux_amd64.tar.gz.sig"},
+		{Name: "tool_linux_amd64.provenance", ContentType: "application/JSON"},
+		{Name: "tool_linux_amd64_notes", ContentType: "text/plain; charset=utf-8"},
+	}}
+
+	got, err := release.SelectAsset(r, "linux", "amd64")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "tool_linux_amd64.tar.gz" {
+		t.Errorf("got %q, want tool_linux_amd64.tar.gz", got.Name)
+	}
+}
+
+func TestSelectAssetExcluding(t *testing.T) {
+	r := &release.Release{TagName: "v1.2.3", Assets: []release.Asset{
+		{Name: "tool_linux_amd64.deb"},
+		{Name: "tool_linux_amd64.txt", ContentType: "text/plain"},
+	}}
+	tests := []struct {
+		name       string
+		exclusions release.AssetExclusions
+		want       string
+	}{
+		{"suffix", release.AssetExclusions{Suffixes: []string{".DEB"}},
+			"tool_linux_amd64.txt"},
+		{"content type", release.AssetExclusions{
+			Suffixes:     []string{".deb"},
+			ContentTypes: []string{"Text/Plain"},
+		}, ""},
+		{"text allowed", release.AssetExclusions{
+			Suffixes: []string{".deb"},
+		}, "tool_linux_amd64.txt"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := release.SelectAssetExcluding(r, "linux", "amd64",
+				tc.exclusions)
+
+			if tc.want == "" {
+				if !errors.Is(err, release.ErrNoAsset) {
+					t.Fatalf("got %v, error %v; want ErrNoAsset", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Name != tc.want {
+				t.Errorf("got %q, want %q", got.Name, tc.want)
+			}
+		})
+	}
+
+	// Nothing excluded: both match.
+	_, err := release.SelectAssetExcluding(r, "linux", "amd64",
+		release.AssetExclusions{})
+	if !errors.Is(err, release.ErrAmbiguousAsset) {
+		t.Errorf("without exclusions: got error %v, want ErrAmbiguousAsset",
+			err)
+	}
+}
+
+func TestDefaultAssetExclusionsIsACopy(t *testing.T) {
+	ex := release.DefaultAssetExclusions()
+	for i := range ex.Suffixes {
+		ex.Suffixes[i] = ".gz"
+	}
+	r := &release.Release{Assets: []release.Asset{
+		{Name: "tool_linux_amd64.tar.gz"},
+	}}
+
+	if _, err := release.SelectAsset(r, "linux", "amd64"); err != nil {
+		t.Errorf("the defaults were modified: %v", err)
+	}
+}
+
+func TestLatestInstallableWithAssetExclusions(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	srv.SetReleases("o", "r", []release.Release{
+		{TagName: "v1.3.0", Assets: []release.Asset{
+			{Name: "tool_linux_amd64.deb"}}},
+		{TagName: "v1.2.0", Assets: []release.Asset{
+			{Name: "tool_linux_amd64.tar.gz"}}},
+	})
+	ex := release.DefaultAssetExclusions()
+	ex.Suffixes = append(ex.Suffixes, ".deb")
+
+	got, err := release.LatestInstallable("o", "r", "v1.0.0", "linux", "amd64",
+		release.WithBaseURL(srv.URL), release.WithAssetExclusions(ex))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.TagName != "v1.2.0" {
+		t.Errorf("got %s, want v1.2.0", got.TagName)
+	}
+}
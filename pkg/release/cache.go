@@ -0,0 +1,86 @@
+package release
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CachedLatest returns the latest release of the GitHub repository owner/repo
+// as stored in the cache of WithCache (which must be among opts) by a
+// previous lookup, however old, without making any request. The age of the
+// value is time.Since(entry.FetchedAt). If the cache has no value, the error
+// wraps ErrNoCachedValue.
+func CachedLatest(owner string, repo string, opts ...Option) (CacheEntry, error) {
+	opts = append(opts[:len(opts):len(opts)], WithOffline())
+	entry, _, err := NewGitHub(owner, repo, opts...).latestFromCache(
+		context.Background())
+	return entry, err
+}
+
+// diskCache stores a CacheEntry per repository in a directory, one JSON file
+// each.
+type diskCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// cacheKey identifies a cache entry. url is unique; name is only to make the
+// file name readable.
+type cacheKey struct {
+	name string
+	url  string
+}
+
+func (c *diskCache) path(key cacheKey) string {
+	sum := sha256.Sum256([]byte(key.url))
+	return filepath.Join(c.dir, fmt.Sprintf("%s_%x.json", key.name, sum[:6]))
+}
+
+// load returns the entry for key. It returns false if there is no entry, or
+// if the entry cannot be read or is corrupt.
+func (c *diskCache) load(key cacheKey) (CacheEntry, bool) {
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return CacheEntry{}, false
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Tag == "" {
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+// store writes the entry for key. It writes to a temporary file and then
+// renames it, so that concurrent processes never read a partially written
+// entry.
+func (c *diskCache) store(key cacheKey, entry CacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	tmp, err := ioutil.TempFile(c.dir, ".tmp-*.json")
+	if err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// ChangelogBetween lists the releases of owner/repo and returns those with a
+// tag strictly greater than fromV and less than or equal to toV, newest first,
+// so that their Body can be concatenated into the changelog of an update.
+// Drafts and tags that are not valid semver are skipped. With WithTagPrefix,
+// the prefix is removed from the tags, and from fromV and toV, before
+// comparing; the returned releases keep their tags.
+//
+// fromV does not need to be one of the releases (it might have been deleted,
+// or be a local build): the selection is only by semver precedence. Only the
+// first page of releases is listed unless WithAllPages is used.
+//
+// With WithTagMessageFallback, the Body of a release without description is
+// the message of its annotated git tag, if any, and BodySource tells which is
+// which. A tag message that cannot be fetched is logged and left empty.
+func ChangelogBetween(owner string, repo string, fromV string, toV string,
+	opts ...Option) ([]Release, error) {
+	ctx := context.Background()
+	gh := NewGitHub(owner, repo, opts...)
+	from := normalize(gh.cfg.tagVersion(fromV))
+	to := normalize(gh.cfg.tagVersion(toV))
+	if !semver.IsValid(from) {
+		return nil, fmt.Errorf("from version is not a valid semver: %s", fromV)
+	}
+	if !semver.IsValid(to) {
+		return nil, fmt.Errorf("to version is not a valid semver: %s", toV)
+	}
+	releases, err := gh.ListReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	selected := between(FilterDrafts(releases), gh.cfg.tagVersion, from, to)
+	if gh.cfg.tagMessages {
+		gh.fillFromTags(ctx, selected)
+	}
+	return selected, nil
+}
+
+// fillFromTags sets the Body of the releases without one to the message of
+// their tag, and the BodySource of all of them.
+func (gh *GitHub) fillFromTags(ctx context.Context, releases []Release) {
+	for i := range releases {
+		r := &releases[i]
+		if strings.TrimSpace(r.Body) != "" {
+			r.BodySource = BodyFromRelease
+			continue
+		}
+		msg, err := gh.TagMessage(ctx, r.TagName)
+		if err != nil {
+			gh.cfg.warn("cannot fetch tag message", "tag", r.TagName,
+				"error", err)
+			continue
+		}
+		if msg != "" {
+			r.Body = msg
+			r.BodySource = BodyFromTag
+		}
+	}
+}
+
+// between returns the releases with the version of their tag, as returned by
+// tagVersion, in (from, to], newest first.
+func between(releases []Release, tagVersion func(string) string, from string,
+	to string) []Release {
+	version := func(r Release) string {
+		return normalize(tagVersion(r.TagName))
+	}
+	var selected []Release
+	for _, r := range releases {
+		v := version(r)
+		if !semver.IsValid(v) {
+			continue
+		}
+		if semver.Compare(v, from) > 0 && semver.Compare(v, to) <= 0 {
+			selected = append(selected, r)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return semver.Compare(version(selected[i]), version(selected[j])) > 0
+	})
+	return selected
+}
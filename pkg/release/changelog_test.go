@@ -0,0 +1,59 @@
+package release_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/marco-m/taschino/pkg/release"
+	"github.com/marco-m/taschino/pkg/release/releasetest"
+)
+
+// tagNames returns the tags of releases.
+func tagNames(releases []release.Release) []string {
+	names := make([]string, len(releases))
+	for i, r := range releases {
+		names[i] = r.TagName
+	}
+	return names
+}
+
+func TestChangelogBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		releases []string
+		from     string
+		to       string
+		opts     []release.Option
+		want     []string
+	}{
+		{"with v", []string{"v1.1.0", "v1.3.0", "v1.2.0", "v1.0.0"},
+			"1.0.0", "v1.2.0", nil, []string{"v1.2.0", "v1.1.0"}},
+		{"without v", []string{"1.10.0", "1.9.0", "1.2.0"}, "v1.2.0",
+			"1.10.0", nil, []string{"1.10.0", "1.9.0"}},
+		{"prefix", []string{"release-1.10.0", "release-1.2.0",
+			"release-1.9.0", "release-1.1.0"}, "1.1.0", "release-1.10.0",
+			[]release.Option{release.WithTagPrefix("release-")},
+			[]string{"release-1.10.0", "release-1.9.0", "release-1.2.0"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := releasetest.NewServer()
+			defer srv.Close()
+			var releases []release.Release
+			for _, tag := range tc.releases {
+				releases = append(releases, release.Release{TagName: tag})
+			}
+			srv.SetReleases("o", "r", releases)
+
+			got, err := release.ChangelogBetween("o", "r", tc.from, tc.to,
+				append(tc.opts, release.WithBaseURL(srv.URL))...)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(tagNames(got)) != fmt.Sprint(tc.want) {
+				t.Errorf("got %v, want %v", tagNames(got), tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package release
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Channels known to LatestForChannel. Any other name is a prerelease
+// identifier, as "beta".
+const (
+	ChannelStable  = "stable"
+	ChannelBeta    = "beta"
+	ChannelRC      = "rc"
+	ChannelNightly = "nightly"
+)
+
+// LatestForChannel lists the releases of owner/repo and returns the highest
+// tag belonging to channel, skipping drafts and tags that are not valid
+// semver. The channel of a tag is given by its semver prerelease part
+// (semver.Prerelease):
+//
+//   - "stable": no prerelease part, such as v2.0.0;
+//   - "nightly": prerelease starting with identifier "nightly" or with a date
+//     (YYYYMMDD or YYYYMMDDhhmmss), such as v2.0.0-nightly.20240601 or
+//     v2.0.0-20240601;
+//   - any other channel, such as "beta" or "rc": prerelease starting with the
+//     identifier channel, such as v2.0.0-beta.3 for "beta" (not v2.0.0-beta3,
+//     whose identifier is "beta3").
+//
+// A channel contains only its own tags: a newer stable release is not
+// returned for channel "beta".
+func LatestForChannel(owner string, repo string, channel string,
+	opts ...Option) (string, error) {
+	return latestFunc(context.Background(), NewGitHub(owner, repo, opts...),
+		func(r Release) bool { return tagChannel(r.TagName, channel) },
+		ErrNoRelease)
+}
+
+// dateIdentifier matches a prerelease identifier made of a date.
+var dateIdentifier = regexp.MustCompile(`^[0-9]{8}([0-9]{6})?$`)
+
+// tagChannel reports whether tag belongs to channel.
+func tagChannel(tag string, channel string) bool {
+	prerelease := strings.TrimPrefix(semver.Prerelease(normalize(tag)), "-")
+	if channel == ChannelStable {
+		return prerelease == ""
+	}
+	first := strings.SplitN(prerelease, ".", 2)[0]
+	if channel == ChannelNightly && dateIdentifier.MatchString(first) {
+		return true
+	}
+	return first != "" && first == channel
+}
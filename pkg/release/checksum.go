@@ -0,0 +1,146 @@
+package release
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ChecksumError is returned when the SHA-256 of an asset does not match the
+// expected one. It wraps ErrChecksumMismatch.
+type ChecksumError struct {
+	Asset    string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s: %s: expected sha256 %s, got %s",
+		ErrChecksumMismatch, e.Asset, e.Expected, e.Actual)
+}
+
+func (e *ChecksumError) Unwrap() error {
+	return ErrChecksumMismatch
+}
+
+// VerifyChecksum downloads the asset called assetName of the release with tag
+// of the GitHub repository owner/repo and verifies its SHA-256 against the
+// checksums file attached to the same release as asset checksumsAssetName
+// (such as "checksums.txt" or "SHA256SUMS"). On mismatch the error is a
+// *ChecksumError. See ParseChecksums for the supported formats.
+func VerifyChecksum(ctx context.Context, owner string, repo string, tag string,
+	assetName string, checksumsAssetName string, opts ...Option) error {
+	gh := NewGitHub(owner, repo, opts...)
+	release, err := gh.ReleaseByTag(ctx, tag)
+	if err != nil {
+		return err
+	}
+	checksums, err := gh.checksums(ctx, release, checksumsAssetName)
+	if err != nil {
+		return err
+	}
+	asset, err := release.Asset(assetName)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	if err := gh.download(ctx, asset, hash, nil); err != nil {
+		return err
+	}
+	return verifyDigest(checksums, assetName, hash.Sum(nil))
+}
+
+// DownloadChecksums downloads the checksums asset called assetName (such as
+// "checksums.txt") of the release with tag of the GitHub repository owner/repo
+// and returns its contents as a map from file name to hex SHA-256 digest, for
+// example to verify many files. See ParseChecksums for the supported formats;
+// a malformed file is an error mentioning the offending line.
+func DownloadChecksums(ctx context.Context, owner string, repo string,
+	tag string, assetName string, opts ...Option) (map[string]string, error) {
+	return NewGitHub(owner, repo, opts...).DownloadChecksums(ctx, tag, assetName)
+}
+
+// DownloadChecksums downloads and parses the checksums asset called assetName
+// of the release with tag. See the function DownloadChecksums.
+func (gh *GitHub) DownloadChecksums(ctx context.Context, tag string,
+	assetName string) (map[string]string, error) {
+	release, err := gh.ReleaseByTag(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+	return gh.checksums(ctx, release, assetName)
+}
+
+// checksums downloads and parses the asset called name of release.
+func (gh *GitHub) checksums(ctx context.Context, release *Release,
+	name string) (map[string]string, error) {
+	asset, err := release.Asset(name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := gh.download(ctx, asset, &buf, nil); err != nil {
+		return nil, err
+	}
+	checksums, err := ParseChecksums(&buf)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return checksums, nil
+}
+
+// verifyDigest verifies that digest is the checksum of name in checksums.
+func verifyDigest(checksums map[string]string, name string,
+	digest []byte) error {
+	expected, ok := checksums[name]
+	if !ok {
+		return fmt.Errorf("no checksum for %s", name)
+	}
+	actual := hex.EncodeToString(digest)
+	if !strings.EqualFold(expected, actual) {
+		return &ChecksumError{Asset: name, Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+// bsdChecksum matches a line in BSD format: "SHA256 (file) = hash".
+var bsdChecksum = regexp.MustCompile(`^SHA256 \((.+)\) = ([0-9a-fA-F]{64})$`)
+
+// gnuChecksum matches a line in GNU format: "hash  file", or "hash *file" for
+// binary mode.
+var gnuChecksum = regexp.MustCompile(`^([0-9a-fA-F]{64}) [ *](.+)$`)
+
+// ParseChecksums parses a file of SHA-256 checksums, as produced by
+// sha256sum (GNU format, "<hash>  <file>") or by shasum --tag (BSD format,
+// "SHA256 (<file>) = <hash>"), into a map from file name to hex digest. Empty
+// lines and lines starting with '#' are ignored.
+func ParseChecksums(r io.Reader) (map[string]string, error) {
+	checksums := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if m := bsdChecksum.FindStringSubmatch(line); m != nil {
+			checksums[m[1]] = strings.ToLower(m[2])
+			continue
+		}
+		if m := gnuChecksum.FindStringSubmatch(line); m != nil {
+			checksums[m[2]] = strings.ToLower(m[1])
+			continue
+		}
+		return nil, fmt.Errorf("parsing checksums: line %d: invalid format: %q",
+			lineno, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("parsing checksums: %w", err)
+	}
+	return checksums, nil
+}
@@ -0,0 +1,104 @@
+package release
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Comparator compares two versions of a versioning scheme, returning 0 if
+// a == b, -1 if a < b, +1 if a > b, or an error if a or b is not valid in the
+// scheme. Use WithComparator to make the functions of this package use it.
+type Comparator interface {
+	Compare(a string, b string) (int, error)
+}
+
+// ComparatorFunc adapts an ordinary function to a Comparator.
+type ComparatorFunc func(a string, b string) (int, error)
+
+// Compare calls f(a, b).
+func (f ComparatorFunc) Compare(a string, b string) (int, error) {
+	return f(a, b)
+}
+
+// SemVer is the Comparator of semantic versions, as Compare. It is the
+// default.
+type SemVer struct{}
+
+// Compare is the package function Compare.
+func (SemVer) Compare(a string, b string) (int, error) {
+	return Compare(a, b)
+}
+
+// CalVer is the Comparator of calendar versions, such as "2024.06.1" or
+// "24.6": dot-separated numeric components, compared numerically from left to
+// right, where a missing component is 0 (so 2024.06 == 2024.6.0). A leading
+// "v" is optional. A suffix after "-", such as "2024.06.1-beta", denotes a
+// prerelease, which precedes the same version without suffix; suffixes
+// compare lexically.
+type CalVer struct{}
+
+// Compare compares the calendar versions a and b.
+func (CalVer) Compare(a string, b string) (int, error) {
+	aParts, aPre, err := parseCalVer(a)
+	if err != nil {
+		return 0, err
+	}
+	bParts, bPre, err := parseCalVer(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var x, y int
+		if i < len(aParts) {
+			x = aParts[i]
+		}
+		if i < len(bParts) {
+			y = bParts[i]
+		}
+		if x != y {
+			return sign(x - y), nil
+		}
+	}
+	switch {
+	case aPre == bPre:
+		return 0, nil
+	case aPre == "":
+		return 1, nil
+	case bPre == "":
+		return -1, nil
+	}
+	return strings.Compare(aPre, bPre), nil
+}
+
+// parseCalVer returns the numeric components and the prerelease suffix of the
+// calendar version v.
+func parseCalVer(v string) ([]int, string, error) {
+	s := strings.TrimPrefix(v, "v")
+	pre := ""
+	if i := strings.Index(s, "-"); i >= 0 {
+		s, pre = s[:i], s[i+1:]
+		if pre == "" {
+			return nil, "", fmt.Errorf("not a valid calendar version: %s", v)
+		}
+	}
+	var parts []int
+	for _, field := range strings.Split(s, ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 || strings.HasPrefix(field, "+") {
+			return nil, "", fmt.Errorf("not a valid calendar version: %s", v)
+		}
+		parts = append(parts, n)
+	}
+	return parts, pre, nil
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CacheEntry is the result of a lookup of the latest release, together with
+// the validators to make a conditional request for the same lookup later.
+// It is meant to be stored by the caller, in memory or on disk.
+type CacheEntry struct {
+	Tag  string `json:"tag"`
+	ETag string `json:"etag,omitempty"`
+	// LastModified is the Last-Modified header of the response, replayed
+	// verbatim as If-Modified-Since when there is no ETag.
+	LastModified string `json:"last_modified,omitempty"`
+	// FetchedAt is when Tag was last fetched or confirmed by the API.
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// GitHubLatestCached is like GitHubLatest, but makes a conditional request
+// with the ETag of prev, the result of a previous call. If the latest release
+// did not change, GitHub replies 304 Not Modified, which does not count against
+// the rate limit, and prev is returned with an updated FetchedAt. Otherwise the
+// new tag is returned, with its new ETag. A zero prev makes an unconditional
+// request.
+//
+// If prev has no ETag but has a LastModified, the request is conditional on
+// it, with If-Modified-Since, instead. When both are available, only the ETag
+// is used, since it is the more precise validator.
+func GitHubLatestCached(owner string, repo string, prev CacheEntry,
+	opts ...Option) (CacheEntry, error) {
+	return NewGitHub(owner, repo, opts...).LatestCached(context.Background(),
+		prev)
+}
+
+// LatestCached returns the tag of the latest release, making a conditional
+// request based on prev. See GitHubLatestCached.
+func (gh *GitHub) LatestCached(ctx context.Context,
+	prev CacheEntry) (CacheEntry, error) {
+	// https://developer.github.com/v3/#conditional-requests
+	header := gh.apiHeader()
+	switch {
+	case prev.Tag == "":
+		// Nothing to revalidate.
+	case prev.ETag != "":
+		header.Set("If-None-Match", prev.ETag)
+	case prev.LastModified != "":
+		header.Set("If-Modified-Since", prev.LastModified)
+	}
+	var release Release
+	var respHeader http.Header
+	err := gh.mirrored(ctx, func(base string, cfg *config) error {
+		api_url := fmt.Sprintf("%s/repos/%s/%s/releases/latest",
+			base, gh.owner, gh.repo)
+		var err error
+		respHeader, err = getJSON(ctx, cfg, api_url, bearerAuth, header,
+			&release)
+		return err
+	})
+	if err == errNotModified {
+		prev.FetchedAt = time.Now()
+		return prev, nil
+	}
+	if err != nil {
+		return CacheEntry{}, err
+	}
+	if release.TagName == "" {
+		return CacheEntry{},
+			fmt.Errorf("parsing JSON response: missing 'field tag_name'")
+	}
+	return CacheEntry{
+		Tag:          release.TagName,
+		ETag:         respHeader.Get("ETag"),
+		LastModified: respHeader.Get("Last-Modified"),
+		FetchedAt:    time.Now(),
+	}, nil
+}
@@ -0,0 +1,270 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Satisfies reports whether version satisfies constraint. The leading "v" of
+// versions is optional, both in version and in constraint.
+//
+// A constraint is a list of comparisons separated by commas, all of which must
+// hold, such as ">=1.0, <2.0". Alternatives are separated by "||", such as
+// "^1.2 || ^2.0". The supported comparisons are:
+//
+//	=1.2.3 or 1.2.3  exactly 1.2.3
+//	1.2              any 1.2.x (same as ~1.2)
+//	!=1.2.3          anything but 1.2.3
+//	>1.2.3 >=1.2.3 <1.2.3 <=1.2.3
+//	^1.2.3           >=1.2.3, <2.0.0 (^0.2.3 is >=0.2.3, <0.3.0)
+//	~1.2.3           >=1.2.3, <1.3.0 (~1 is >=1.0.0, <2.0.0)
+//	*                any version
+//
+// A prerelease version, such as 1.2.3-rc.1, satisfies a constraint only if one
+// of its comparisons (in the same alternative) mentions a prerelease of the
+// same 1.2.3 version, such as ">=1.2.3-rc.0". This way ">=1.0, <2.0" never
+// selects a prerelease, while a user opting in to a specific prerelease line
+// gets it.
+func Satisfies(version string, constraint string) (bool, error) {
+	c, err := parseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	v := normalize(version)
+	if !semver.IsValid(v) {
+		return false, fmt.Errorf("version is not a valid semver: %s", version)
+	}
+	return c.match(v), nil
+}
+
+// LatestMatching lists the releases of owner/repo and returns the highest tag
+// satisfying constraint, skipping drafts and tags that are not valid semver.
+// See Satisfies for the syntax of constraint.
+func LatestMatching(owner string, repo string, constraint string,
+	opts ...Option) (string, error) {
+	c, err := parseConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+	return latestFunc(context.Background(), NewGitHub(owner, repo, opts...),
+		func(r Release) bool { return c.match(normalize(r.TagName)) },
+		ErrNoRelease)
+}
+
+// ReleasesInRange lists the releases of owner/repo and returns those whose tag
+// satisfies constraint, sorted in ascending semver order, skipping drafts and
+// tags that are not valid semver. See Satisfies for the syntax of constraint.
+// To know how many tags were skipped because not valid semver, use the method
+// GitHub.ReleasesInRange.
+func ReleasesInRange(owner string, repo string, constraint string,
+	opts ...Option) ([]Release, error) {
+	releases, _, err := NewGitHub(owner, repo, opts...).ReleasesInRange(
+		context.Background(), constraint)
+	return releases, err
+}
+
+// ReleasesInRange returns the releases whose tag satisfies constraint, in
+// ascending semver order, and the number of releases skipped because their
+// tag is not valid semver. See the function ReleasesInRange.
+func (gh *GitHub) ReleasesInRange(ctx context.Context,
+	constraint string) ([]Release, int, error) {
+	c, err := parseConstraint(constraint)
+	if err != nil {
+		return nil, 0, err
+	}
+	releases, err := gh.ListReleases(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	var matching []Release
+	var versions []string
+	skipped := 0
+	for _, r := range FilterDrafts(releases) {
+		v := normalize(gh.cfg.tagVersion(r.TagName))
+		if !semver.IsValid(v) {
+			skipped++
+			continue
+		}
+		if c.match(v) {
+			matching = append(matching, r)
+			versions = append(versions, v)
+		}
+	}
+	sort.Stable(byVersion{matching, versions})
+	return matching, skipped, nil
+}
+
+// byVersion sorts releases by the corresponding versions.
+type byVersion struct {
+	releases []Release
+	versions []string
+}
+
+func (s byVersion) Len() int {
+	return len(s.releases)
+}
+
+func (s byVersion) Less(i, j int) bool {
+	return semver.Compare(s.versions[i], s.versions[j]) < 0
+}
+
+func (s byVersion) Swap(i, j int) {
+	s.releases[i], s.releases[j] = s.releases[j], s.releases[i]
+	s.versions[i], s.versions[j] = s.versions[j], s.versions[i]
+}
+
+// constraint is a list of alternatives, each of which is a list of
+// comparisons that must all hold.
+type constraint [][]comparison
+
+// comparison compares a version against version with op, one of "=", "!=",
+// ">", ">=", "<", "<=". version is in canonical form.
+type comparison struct {
+	op      string
+	version string
+}
+
+func (c constraint) match(v string) bool {
+	for _, alternative := range c {
+		if matchAll(alternative, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAll(comparisons []comparison, v string) bool {
+	if semver.Prerelease(v) != "" && !mentionsPrerelease(comparisons, v) {
+		return false
+	}
+	for _, cmp := range comparisons {
+		if !cmp.match(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// mentionsPrerelease reports whether one of comparisons is against a
+// prerelease of the same major.minor.patch as v.
+func mentionsPrerelease(comparisons []comparison, v string) bool {
+	for _, cmp := range comparisons {
+		if semver.Prerelease(cmp.version) != "" &&
+			core(cmp.version) == core(semver.Canonical(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (cmp comparison) match(v string) bool {
+	c := semver.Compare(v, cmp.version)
+	switch cmp.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	}
+	return false
+}
+
+func parseConstraint(s string) (constraint, error) {
+	var c constraint
+	for _, alternative := range strings.Split(s, "||") {
+		var comparisons []comparison
+		for _, term := range strings.Split(alternative, ",") {
+			cmps, err := parseTerm(strings.TrimSpace(term))
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", s, err)
+			}
+			comparisons = append(comparisons, cmps...)
+		}
+		c = append(c, comparisons)
+	}
+	return c, nil
+}
+
+// parseTerm parses a single term of a constraint, such as ">=1.2" or "^1.2.3",
+// into the equivalent comparisons.
+func parseTerm(term string) ([]comparison, error) {
+	if term == "*" {
+		return nil, nil
+	}
+	op := ""
+	for _, candidate := range []string{"!=", ">=", "<=", "=", ">", "<", "^", "~"} {
+		if strings.HasPrefix(term, candidate) {
+			op = candidate
+			break
+		}
+	}
+	raw := strings.TrimSpace(strings.TrimPrefix(term, op))
+	if raw == "" {
+		return nil, fmt.Errorf("missing version in %q", term)
+	}
+	v := normalize(raw)
+	if !semver.IsValid(v) {
+		return nil, fmt.Errorf("not a valid semver: %s", raw)
+	}
+	lower := semver.Canonical(v)
+	major, minor, patch, given := parts(v)
+	partial := given < 3 && semver.Prerelease(v) == ""
+
+	var upper string
+	switch {
+	case op == "^":
+		switch {
+		case major > 0 || given == 1:
+			upper = fmt.Sprintf("v%d.0.0", major+1)
+		case minor > 0 || given == 2:
+			upper = fmt.Sprintf("v0.%d.0", minor+1)
+		default:
+			upper = fmt.Sprintf("v0.0.%d", patch+1)
+		}
+	case op == "~" || (op == "=" || op == "") && partial:
+		if given == 1 {
+			upper = fmt.Sprintf("v%d.0.0", major+1)
+		} else {
+			upper = fmt.Sprintf("v%d.%d.0", major, minor+1)
+		}
+	case op == "":
+		op = "="
+	}
+	if upper != "" {
+		return []comparison{{">=", lower}, {"<", upper}}, nil
+	}
+	return []comparison{{op, lower}}, nil
+}
+
+// parts returns the numeric components of the valid semver v and how many of
+// them are given (v1.2 has 2, v1.2.3 has 3).
+func parts(v string) (major, minor, patch, given int) {
+	fields := strings.Split(strings.TrimPrefix(core(v), "v"), ".")
+	nums := make([]int, 3)
+	for i, f := range fields {
+		// Cannot fail: v is valid semver.
+		nums[i], _ = strconv.Atoi(f)
+	}
+	return nums[0], nums[1], nums[2], len(fields)
+}
+
+// core returns the valid semver v without prerelease and build metadata,
+// such as "v1.2.3" for "v1.2.3-rc.1+build".
+func core(v string) string {
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		return v[:i]
+	}
+	return v
+}
@@ -0,0 +1,61 @@
+package release
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// CosignVerifier returns a Verifier of the signatures made by
+// "cosign sign-blob --key" with the private key corresponding to publicKeyPEM,
+// the contents of the "cosign.pub" file. The signature asset is the ".sig" file
+// produced by cosign: the base64 encoding of an ECDSA signature of the SHA-256
+// of the asset.
+//
+// Keyless signatures (with a Fulcio certificate and a Rekor transparency log
+// entry) are not supported: verifying them requires the sigstore libraries,
+// which can be plugged in with VerifierFunc.
+func CosignVerifier(publicKeyPEM []byte) (Verifier, error) {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("cosign: public key: no PEM PUBLIC KEY block")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("cosign: public key: %w", err)
+	}
+	publicKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("cosign: public key: unsupported type %T", key)
+	}
+
+	return VerifierFunc(func(data io.Reader, signature []byte) error {
+		der, err := base64.StdEncoding.DecodeString(
+			string(bytes.TrimSpace(signature)))
+		if err != nil {
+			return fmt.Errorf("cosign: invalid signature encoding: %w", err)
+		}
+		var sig struct {
+			R, S *big.Int
+		}
+		if rest, err := asn1.Unmarshal(der, &sig); err != nil || len(rest) > 0 {
+			return errors.New("cosign: invalid signature format")
+		}
+		hash := sha256.New()
+		if _, err := io.Copy(hash, data); err != nil {
+			return err
+		}
+		if !ecdsa.Verify(publicKey, hash.Sum(nil), sig.R, sig.S) {
+			return errors.New("cosign: invalid signature")
+		}
+		return nil
+	}), nil
+}
@@ -0,0 +1,97 @@
+package release
+
+import (
+	"fmt"
+
+	"golang.org/x/mod/semver"
+)
+
+// Change is the most significant semver component that differs between two
+// versions.
+type Change int
+
+const (
+	// NoChange means that the two versions have the same precedence.
+	NoChange Change = iota
+	// MajorChange means that the two versions differ in the major component.
+	MajorChange
+	// MinorChange means that the two versions differ in the minor component.
+	MinorChange
+	// PatchChange means that the two versions differ in the patch component.
+	PatchChange
+	// PrereleaseChange means that the two versions differ only in the
+	// prerelease component, such as v1.2.3-rc.1 and v1.2.3.
+	PrereleaseChange
+)
+
+// changeNames are the names of the values of Change, as returned by String
+// and used in JSON.
+var changeNames = []string{
+	NoChange:         "none",
+	MajorChange:      "major",
+	MinorChange:      "minor",
+	PatchChange:      "patch",
+	PrereleaseChange: "prerelease",
+}
+
+// String returns the name of c: "none", "major", "minor", "patch" or
+// "prerelease".
+func (c Change) String() string {
+	if c < 0 || int(c) >= len(changeNames) {
+		return fmt.Sprintf("Change(%d)", int(c))
+	}
+	return changeNames[c]
+}
+
+// MarshalText encodes c as its name, so that in JSON it is a string such as
+// "major" rather than a number.
+func (c Change) MarshalText() ([]byte, error) {
+	if c < 0 || int(c) >= len(changeNames) {
+		return nil, fmt.Errorf("invalid change %d", int(c))
+	}
+	return []byte(changeNames[c]), nil
+}
+
+// UnmarshalText decodes the name of a Change, as encoded by MarshalText.
+func (c *Change) UnmarshalText(text []byte) error {
+	for i, name := range changeNames {
+		if string(text) == name {
+			*c = Change(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid change %q", text)
+}
+
+// Delta is the detailed result of CompareDetailed. In JSON, it is an object
+// such as {"direction":-1,"change":"minor"}.
+type Delta struct {
+	// Direction is the result of Compare: 0, -1 or +1.
+	Direction int `json:"direction"`
+	// Change is the most significant component that differs.
+	Change Change `json:"change"`
+}
+
+// CompareDetailed is like Compare, but returns also which component changed,
+// for example to tell a "major update" from a "patch update".
+func CompareDetailed(curV string, latestV string) (Delta, error) {
+	direction, err := Compare(curV, latestV)
+	if err != nil {
+		return Delta{}, err
+	}
+	cur, latest := normalize(curV), normalize(latestV)
+	var change Change
+	switch {
+	case direction == 0:
+		change = NoChange
+	case semver.Major(cur) != semver.Major(latest):
+		change = MajorChange
+	case semver.MajorMinor(cur) != semver.MajorMinor(latest):
+		change = MinorChange
+	case core(semver.Canonical(cur)) != core(semver.Canonical(latest)):
+		change = PatchChange
+	default:
+		change = PrereleaseChange
+	}
+	return Delta{Direction: direction, Change: change}, nil
+}
@@ -0,0 +1,162 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DownloadAsset downloads the asset called assetName of the release with tag
+// of the GitHub repository owner/repo and writes it to w. If the release has
+// no such asset, the error wraps ErrNoAsset. With a token, the asset is
+// downloaded through the API, which works also for private repositories.
+//
+// The timeout of WithTimeout applies only to the lookup of the release, not to
+// the download, which can take much longer; use ctx to bound it.
+func DownloadAsset(ctx context.Context, owner string, repo string, tag string,
+	assetName string, w io.Writer, opts ...Option) error {
+	return NewGitHub(owner, repo, opts...).DownloadAsset(ctx, tag, assetName, w)
+}
+
+// DownloadAsset downloads the asset called assetName of the release with tag
+// and writes it to w. See the function DownloadAsset.
+func (gh *GitHub) DownloadAsset(ctx context.Context, tag string,
+	assetName string, w io.Writer) error {
+	return gh.DownloadAssetProgress(ctx, tag, assetName, w, nil)
+}
+
+// DownloadAssetProgress is like DownloadAsset, but calls onProgress as the
+// download proceeds, with the number of bytes downloaded so far and the total
+// size, from the Content-Length of the response, or -1 if unknown.
+func DownloadAssetProgress(ctx context.Context, owner string, repo string,
+	tag string, assetName string, w io.Writer,
+	onProgress func(downloaded, total int64), opts ...Option) error {
+	return NewGitHub(owner, repo, opts...).DownloadAssetProgress(ctx, tag,
+		assetName, w, onProgress)
+}
+
+// DownloadAssetProgress is like DownloadAsset, but calls onProgress as the
+// download proceeds. See the function DownloadAssetProgress.
+func (gh *GitHub) DownloadAssetProgress(ctx context.Context, tag string,
+	assetName string, w io.Writer,
+	onProgress func(downloaded, total int64)) error {
+	release, err := gh.ReleaseByTag(ctx, tag)
+	if err != nil {
+		return err
+	}
+	asset, err := release.Asset(assetName)
+	if err != nil {
+		return err
+	}
+	return gh.download(ctx, asset, w, onProgress)
+}
+
+// download writes the contents of asset to w, reporting to onProgress if not
+// nil.
+func (gh *GitHub) download(ctx context.Context, asset *Asset, w io.Writer,
+	onProgress func(downloaded, total int64)) error {
+	resp, err := gh.openAsset(ctx, asset, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download %s: unexpected status %s", asset.Name,
+			resp.Status)
+	}
+	if onProgress != nil {
+		w = &progressWriter{w: w, total: resp.ContentLength, fn: onProgress}
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	return nil
+}
+
+// openAsset sends the request for the contents of asset, adding header, and
+// returns the response, whose body the caller must close. Statuses denoting a
+// known failure are returned as errors.
+//
+// With a token, the request goes to the API URL of the asset, which, contrary
+// to the browser URL, works also for private repositories and redirects to a
+// signed URL of the storage of the assets. The token is not sent to the
+// target of the redirect.
+func (gh *GitHub) openAsset(ctx context.Context, asset *Asset,
+	header http.Header) (*http.Response, error) {
+	assetURL := asset.BrowserDownloadURL
+	if gh.cfg.token != "" && asset.URL != "" {
+		assetURL = asset.URL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, assetURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create http request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("User-Agent", gh.cfg.userAgent)
+	if assetURL == asset.URL {
+		// https://docs.github.com/en/rest/releases/assets#get-a-release-asset
+		req.Header.Set("Accept", "application/octet-stream")
+	}
+	if gh.cfg.token != "" {
+		bearerAuth(req, gh.cfg.token)
+	}
+	gh.cfg.debug("download", "asset", asset.Name, "url", assetURL,
+		"range", req.Header.Get("Range"))
+	resp, err := withoutAuthOnRedirect(gh.cfg.client).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http client Do: %w", err)
+	}
+	gh.cfg.debug("response", "url", assetURL, "status", resp.StatusCode,
+		"content-length", resp.ContentLength)
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s not found at %s", ErrNoAsset,
+			asset.Name, assetURL)
+	}
+	if err := checkStatus(gh.cfg, resp, assetURL); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// withoutAuthOnRedirect returns a copy of client that, on a redirect to
+// another host, does not forward the Authorization header. net/http already
+// drops it when the new host is not the same or a subdomain, but a signed
+// storage URL must never receive the token, whatever the domains involved.
+func withoutAuthOnRedirect(client *http.Client) *http.Client {
+	c := *client
+	checkRedirect := client.CheckRedirect
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if req.URL.Host != via[0].URL.Host {
+			req.Header.Del("Authorization")
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return &c
+}
+
+// progressWriter is an io.Writer calling fn after each write to w.
+type progressWriter struct {
+	w       io.Writer
+	written int64
+	total   int64
+	fn      func(downloaded, total int64)
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+	pw.written += int64(n)
+	pw.fn(pw.written, pw.total)
+	return n, err
+}
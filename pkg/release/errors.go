@@ -0,0 +1,122 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoRelease is returned, wrapped, when the requested release does not
+// exist. Use errors.Is to check for it.
+var ErrNoRelease = errors.New("no release found")
+
+// ErrNoStableRelease is returned, wrapped, when there are releases but none of
+// them is stable (that is, they are all drafts, prereleases or not semver).
+var ErrNoStableRelease = errors.New("no stable release found")
+
+// ErrNoPrerelease is returned, wrapped, when there are releases but none of
+// them is a prerelease (ignoring drafts and tags that are not semver).
+var ErrNoPrerelease = errors.New("no prerelease found")
+
+// ErrNoInstallableUpdate is returned, wrapped, by LatestInstallable when no
+// release newer than the current version has an asset for the platform.
+var ErrNoInstallableUpdate = errors.New("no installable update")
+
+// ErrNoAsset is returned, wrapped, when a release does not have the requested
+// asset.
+var ErrNoAsset = errors.New("no such asset")
+
+// ErrAmbiguousAsset is returned, wrapped, when more than one asset of a
+// release matches the selection.
+var ErrAmbiguousAsset = errors.New("more than one asset matches")
+
+// ErrChecksumMismatch is returned, wrapped, when the checksum of an asset does
+// not match the expected one.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ErrBadSignature is returned, wrapped, when the signature of an asset is not
+// valid.
+var ErrBadSignature = errors.New("bad signature")
+
+// ErrNoCachedValue is returned, wrapped, in offline mode when the cache has no
+// value for the requested lookup.
+var ErrNoCachedValue = errors.New("no cached value")
+
+// ErrResponseTooLarge means that the body of a response is bigger than the
+// limit of WithMaxResponseSize.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// ErrRateLimited is returned, wrapped, when the API refused the request
+// because the rate limit has been exceeded.
+var ErrRateLimited = errors.New("API rate limit exceeded")
+
+// ErrUnauthorized is returned, wrapped, when the API refused the request
+// because the token is missing, invalid or lacks permissions.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ErrUnavailable is returned, wrapped, when the API is temporarily not
+// available (status 502, 503 or 504).
+var ErrUnavailable = errors.New("service unavailable")
+
+// APIError is returned when the API answered with a status denoting a failure.
+// It exposes the HTTP context of the response and wraps the error describing
+// the failure, so that errors.Is with the sentinel errors of this package and
+// errors.As with a *RateLimitError keep working.
+type APIError struct {
+	// URL is the URL of the failed request.
+	URL string
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// RateLimitRemaining is the number of requests remaining in the rate
+	// limit window, from the X-RateLimit-Remaining header. It is -1 if the API
+	// did not send it.
+	RateLimitRemaining int
+	// RateLimitReset is when the rate limit window resets, from the
+	// X-RateLimit-Reset header. It is the zero time if the API did not send
+	// it.
+	RateLimitReset time.Time
+	// Err describes the failure.
+	Err error
+}
+
+func (e *APIError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError is returned when the API refused the request because the rate
+// limit has been exceeded. It wraps ErrRateLimited.
+type RateLimitError struct {
+	// URL is the URL of the refused request.
+	URL string
+	// Reset is when the rate limit window resets, from the X-RateLimit-Reset
+	// header. It is the zero time if the API did not send it.
+	Reset time.Time
+	// RetryAfter is how long to wait before retrying, from the Retry-After
+	// header sent on secondary rate limits. It is 0 if the API did not send it.
+	RetryAfter time.Duration
+	// Secondary is true for a secondary rate limit, which GitHub imposes on
+	// bursts of requests independently of the remaining quota.
+	Secondary bool
+	// Message is the message of the response, if any.
+	Message string
+}
+
+func (e *RateLimitError) Error() string {
+	kind := ""
+	if e.Secondary {
+		kind = " (secondary rate limit)"
+	}
+	if e.Reset.IsZero() {
+		return fmt.Sprintf("%s%s at %s", ErrRateLimited, kind, e.URL)
+	}
+	return fmt.Sprintf("%s%s at %s (resets at %s)", ErrRateLimited, kind,
+		e.URL, e.Reset.Format(time.RFC3339))
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
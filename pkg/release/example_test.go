@@ -0,0 +1,32 @@
+package release_test
+
+import (
+	"fmt"
+
+	"github.com/marco-m/taschino/pkg/release"
+	"github.com/marco-m/taschino/pkg/release/releasetest"
+)
+
+func ExampleLatestFunc() {
+	// A fake GitHub API, instead of https://api.github.com.
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	checksums := []release.Asset{{Name: "checksums.txt"}}
+	srv.SetReleases("owner", "repo", []release.Release{
+		{TagName: "v1.3.0-rc.1", Prerelease: true, Assets: checksums},
+		{TagName: "v1.2.0"},
+		{TagName: "v1.1.0", Assets: checksums},
+	})
+
+	// The highest stable release with a checksums file.
+	tag, err := release.LatestFunc("owner", "repo", func(r release.Release) bool {
+		_, err := r.Asset("checksums.txt")
+		return !r.Prerelease && err == nil
+	}, release.WithBaseURL(srv.URL))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(tag)
+	// Output: v1.1.0
+}
@@ -0,0 +1,368 @@
+package release
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ExtractTarGz extracts the gzip-compressed tar archive read from r into
+// destDir, creating it if needed. With WithStripTopDir, it strips the leading
+// directory component of each entry, for archives with a single top-level
+// directory.
+//
+// It refuses entries that would escape destDir (absolute paths, ".." paths,
+// links pointing outside). It preserves the permission bits, so that
+// executables stay executable. Entries other than directories, regular files
+// and links are skipped.
+//
+// If ctx is canceled, it stops between entries, or while writing a file, which
+// is then removed, and returns the error of ctx. The entries already written
+// are left in destDir.
+func ExtractTarGz(ctx context.Context, r io.Reader, destDir string,
+	opts ...Option) error {
+	cfg := newConfig(opts)
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("extract: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return fmt.Errorf("extract: %w", err)
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("extract: %w", err)
+		}
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("extract: %w", err)
+		}
+		name, ok, err := entryPath(hdr.Name, cfg.stripTopDir)
+		if err != nil {
+			return fmt.Errorf("extract: %w", err)
+		}
+		if !ok {
+			continue
+		}
+		if err := checkParents(destDir, name); err != nil {
+			return fmt.Errorf("extract: %w", err)
+		}
+		target := filepath.Join(destDir, name)
+		mode := os.FileMode(hdr.Mode).Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, mode|0o700)
+		case tar.TypeReg, tar.TypeRegA:
+			err = writeFile(target, ctxReader{ctx: ctx, r: tr}, mode)
+		case tar.TypeSymlink:
+			err = symlink(destDir, name, hdr.Linkname)
+		case tar.TypeLink:
+			var linkName string
+			linkName, ok, err = entryPath(hdr.Linkname, cfg.stripTopDir)
+			if err == nil && ok {
+				err = hardLink(destDir, linkName, target)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("extract: %s: %w", hdr.Name, err)
+		}
+	}
+}
+
+// ExtractZip extracts the zip archive read from r, of size bytes, into
+// destDir, creating it if needed. It has the same options and protections as
+// ExtractTarGz, and is canceled by ctx in the same way. It preserves the
+// permission bits for archives storing Unix modes; files of archives made on
+// Windows get the default mode.
+func ExtractZip(ctx context.Context, r io.ReaderAt, size int64, destDir string,
+	opts ...Option) error {
+	cfg := newConfig(opts)
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("extract: %w", err)
+	}
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return fmt.Errorf("extract: %w", err)
+	}
+	for _, f := range zr.File {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("extract: %w", err)
+		}
+		name, ok, err := entryPath(f.Name, cfg.stripTopDir)
+		if err != nil {
+			return fmt.Errorf("extract: %w", err)
+		}
+		if !ok {
+			continue
+		}
+		if err := checkParents(destDir, name); err != nil {
+			return fmt.Errorf("extract: %w", err)
+		}
+		if err := extractZipFile(ctx, f, destDir, name); err != nil {
+			return fmt.Errorf("extract: %s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// zipCreatorUnix is the "version made by" of zip archives storing Unix modes.
+const zipCreatorUnix = 3
+
+// extractZipFile extracts f as name, relative to destDir.
+func extractZipFile(ctx context.Context, f *zip.File, destDir string,
+	name string) error {
+	target := filepath.Join(destDir, name)
+	mode := f.Mode()
+	if f.CreatorVersion>>8 != zipCreatorUnix {
+		// No Unix mode stored: use a safe default rather than 0666.
+		mode = mode&^os.ModePerm | 0o644
+		if mode.IsDir() {
+			mode |= 0o111
+		}
+	}
+	switch {
+	case mode.IsDir():
+		return os.MkdirAll(target, mode.Perm()|0o700)
+	case mode&os.ModeSymlink != 0:
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		linkName, err := ioutil.ReadAll(io.LimitReader(rc, 4096))
+		if err != nil {
+			return err
+		}
+		return symlink(destDir, name, string(linkName))
+	case mode.IsRegular():
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		return writeFile(target, ctxReader{ctx: ctx, r: rc}, mode.Perm())
+	}
+	return nil
+}
+
+// DownloadAndExtract downloads the asset called assetName of the release with
+// tag of the GitHub repository owner/repo and extracts it into destDir. The
+// asset must be a ".tar.gz", ".tgz" or ".zip" archive. See ExtractTarGz and
+// ExtractZip. Canceling ctx stops both the download and the extraction; the
+// temporary download of a zip archive is removed.
+func DownloadAndExtract(ctx context.Context, owner string, repo string,
+	tag string, assetName string, destDir string, opts ...Option) error {
+	gh := NewGitHub(owner, repo, opts...)
+	name := strings.ToLower(assetName)
+	isZip := strings.HasSuffix(name, ".zip")
+	if !isZip && !strings.HasSuffix(name, ".tar.gz") &&
+		!strings.HasSuffix(name, ".tgz") {
+		return fmt.Errorf("extract: %s: unsupported archive format", assetName)
+	}
+	release, err := gh.ReleaseByTag(ctx, tag)
+	if err != nil {
+		return err
+	}
+	asset, err := release.Asset(assetName)
+	if err != nil {
+		return err
+	}
+
+	if isZip {
+		// A zip archive needs random access: download it first.
+		tmp, err := ioutil.TempFile("", "taschino-*.zip")
+		if err != nil {
+			return fmt.Errorf("extract: %w", err)
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+		if err := gh.download(ctx, asset, tmp, nil); err != nil {
+			return err
+		}
+		info, err := tmp.Stat()
+		if err != nil {
+			return fmt.Errorf("extract: %w", err)
+		}
+		return ExtractZip(ctx, tmp, info.Size(), destDir, opts...)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(gh.download(ctx, asset, pw, nil))
+	}()
+	err = ExtractTarGz(ctx, pr, destDir, opts...)
+	pr.Close()
+	return err
+}
+
+// entryPath returns the cleaned, relative path of the archive entry name,
+// with the leading directory stripped if strip. It returns false if the entry
+// must be skipped and an error if it would escape the destination directory.
+func entryPath(name string, strip bool) (string, bool, error) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	if path.IsAbs(name) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", false, fmt.Errorf("absolute path %s", name)
+	}
+	cleaned := path.Clean(name)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", false, fmt.Errorf("path %s escapes destination", name)
+	}
+	if strip {
+		i := strings.Index(cleaned, "/")
+		if i < 0 {
+			// The top-level directory itself.
+			return "", false, nil
+		}
+		cleaned = cleaned[i+1:]
+	}
+	if cleaned == "." {
+		return "", false, nil
+	}
+	return filepath.FromSlash(cleaned), true, nil
+}
+
+// checkParents returns an error if a parent directory of name, relative to
+// destDir, is a symbolic link: even if each link points inside destDir, a
+// chain of links could lead outside of it.
+func checkParents(destDir string, name string) error {
+	dir := destDir
+	parts := strings.Split(filepath.Dir(name), string(filepath.Separator))
+	for _, part := range parts {
+		if part == "." {
+			continue
+		}
+		dir = filepath.Join(dir, part)
+		info, err := os.Lstat(dir)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("path %s traverses symbolic link %s", name, dir)
+		}
+	}
+	return nil
+}
+
+// writeFile creates the file target with the contents of r and mode. On error,
+// the partial file is removed.
+func writeFile(target string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	// Do not write through an existing symbolic link.
+	_ = os.Remove(target)
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(target)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(target)
+		return err
+	}
+	// OpenFile applies the umask; set the mode of the archive.
+	return os.Chmod(target, mode)
+}
+
+// hardLink creates the hard link target to linkName, relative to destDir,
+// refusing a linkName reached through, or being, a symbolic link: some systems
+// follow it, and it could point outside destDir.
+func hardLink(destDir string, linkName string, target string) error {
+	if err := checkParents(destDir, linkName); err != nil {
+		return err
+	}
+	source := filepath.Join(destDir, linkName)
+	info, err := os.Lstat(source)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("hard link to symbolic link %s", linkName)
+	}
+	return os.Link(source, target)
+}
+
+// ctxReader reads from r until ctx is done, and then fails with the error of
+// ctx, so that copying a big archive entry stops promptly on cancellation.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// symlink creates the symbolic link name, relative to destDir, pointing to
+// linkName, refusing targets that escape destDir. The check is lexical, so
+// linkName must not be resolved differently by the filesystem: it may go up
+// (with "..") only at its start and must not traverse an existing symbolic
+// link, which could point to "." and turn a later ".." into a way out.
+func symlink(destDir string, name string, linkName string) error {
+	linkName = strings.ReplaceAll(linkName, `\`, "/")
+	if path.IsAbs(linkName) || filepath.VolumeName(linkName) != "" {
+		return fmt.Errorf("link to absolute path %s", linkName)
+	}
+	dir := path.Dir(filepath.ToSlash(name))
+	parts := strings.Split(linkName, "/")
+	descended := false
+	for i, part := range parts {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if descended {
+				return fmt.Errorf("link %s goes up after going down", linkName)
+			}
+		default:
+			descended = true
+			if i == len(parts)-1 {
+				// The link may point to a link.
+				break
+			}
+			prefix := path.Join(dir, path.Join(parts[:i+1]...))
+			if prefix == ".." || strings.HasPrefix(prefix, "../") {
+				break
+			}
+			info, err := os.Lstat(filepath.Join(destDir,
+				filepath.FromSlash(prefix)))
+			if err == nil && info.Mode()&os.ModeSymlink != 0 {
+				return fmt.Errorf("link %s traverses symbolic link %s",
+					linkName, prefix)
+			}
+		}
+	}
+	resolved := path.Join(dir, linkName)
+	if resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return fmt.Errorf("link %s escapes destination", linkName)
+	}
+	target := filepath.Join(destDir, name)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	return os.Symlink(filepath.FromSlash(linkName), target)
+}
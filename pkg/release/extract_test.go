@@ -0,0 +1,229 @@
+package release
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// entry is an entry of a test archive.
+type entry struct {
+	name string
+	// link is the target of a symbolic link, or of a hard link if hard.
+	link string
+	hard bool
+	dir  bool
+	body string
+}
+
+func tarGz(t *testing.T, entries []entry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0o644}
+		switch {
+		case e.dir:
+			hdr.Typeflag, hdr.Mode = tar.TypeDir, 0o755
+		case e.hard:
+			hdr.Typeflag, hdr.Linkname = tar.TypeLink, e.link
+		case e.link != "":
+			hdr.Typeflag, hdr.Linkname = tar.TypeSymlink, e.link
+		default:
+			hdr.Typeflag, hdr.Size = tar.TypeReg, int64(len(e.body))
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T, entries []entry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &zip.FileHeader{Name: e.name}
+		body := e.body
+		switch {
+		case e.dir:
+			hdr.SetMode(os.ModeDir | 0o755)
+		case e.link != "":
+			hdr.SetMode(os.ModeSymlink | 0o777)
+			body = e.link
+		default:
+			hdr.SetMode(0o644)
+		}
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// sandbox returns a destination directory and the path of a secret file next
+// to it, outside of it.
+func sandbox(t *testing.T) (string, string) {
+	t.Helper()
+	root, err := ioutil.TempDir("", "taschino-extract-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(root) })
+	secret := filepath.Join(root, "secret")
+	if err := ioutil.WriteFile(secret, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(root, "dest")
+	if err := os.Mkdir(dest, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return dest, secret
+}
+
+// escapeEntries make l1 resolve to the parent of the destination through l2,
+// a link to ".", and hard link the secret file through l1.
+var escapeEntries = []entry{
+	{name: "x/", dir: true},
+	{name: "l2", link: "."},
+	{name: "l1", link: "l2/x/../.."},
+	{name: "stolen", link: "l1/secret", hard: true},
+}
+
+func TestExtractTarGzRefusesSymlinkEscape(t *testing.T) {
+	dest, _ := sandbox(t)
+	archive := tarGz(t, escapeEntries)
+
+	err := ExtractTarGz(context.Background(), bytes.NewReader(archive), dest)
+
+	if err == nil || !strings.Contains(err.Error(), "l1") {
+		t.Fatalf("got error %v, want an error about l1", err)
+	}
+	assertNotCreated(t, dest, "l1", "stolen")
+}
+
+func TestExtractTarGzRefusesHardLinkThroughSymlink(t *testing.T) {
+	dest, _ := sandbox(t)
+	// up resolves outside; the hard link would then reach the secret.
+	if err := os.Symlink("..", filepath.Join(dest, "up")); err != nil {
+		t.Fatal(err)
+	}
+	archive := tarGz(t, []entry{
+		{name: "stolen", link: "up/secret", hard: true},
+	})
+
+	err := ExtractTarGz(context.Background(), bytes.NewReader(archive), dest)
+
+	if err == nil {
+		t.Fatal("got no error, want a refused hard link")
+	}
+	assertNotCreated(t, dest, "stolen")
+}
+
+func TestExtractTarGzRefusesHardLinkToSymlink(t *testing.T) {
+	dest, _ := sandbox(t)
+	if err := os.Symlink("../secret", filepath.Join(dest, "up")); err != nil {
+		t.Fatal(err)
+	}
+	archive := tarGz(t, []entry{
+		{name: "stolen", link: "up", hard: true},
+	})
+
+	err := ExtractTarGz(context.Background(), bytes.NewReader(archive), dest)
+
+	if err == nil {
+		t.Fatal("got no error, want a refused hard link")
+	}
+	assertNotCreated(t, dest, "stolen")
+}
+
+func TestExtractZipRefusesSymlinkEscape(t *testing.T) {
+	dest, _ := sandbox(t)
+	// Zip has no hard links: the symbolic links alone are the escape.
+	archive := zipArchive(t, escapeEntries[:3])
+
+	err := ExtractZip(context.Background(), bytes.NewReader(archive),
+		int64(len(archive)), dest)
+
+	if err == nil || !strings.Contains(err.Error(), "l1") {
+		t.Fatalf("got error %v, want an error about l1", err)
+	}
+	assertNotCreated(t, dest, "l1")
+}
+
+func TestExtractZipRefusesUpAfterDown(t *testing.T) {
+	dest, _ := sandbox(t)
+	// a does not exist yet when l1 is created, but becomes a link to ".".
+	archive := zipArchive(t, []entry{
+		{name: "l1", link: "a/x/../.."},
+		{name: "a", link: "."},
+	})
+
+	err := ExtractZip(context.Background(), bytes.NewReader(archive),
+		int64(len(archive)), dest)
+
+	if err == nil {
+		t.Fatal("got no error, want a refused link")
+	}
+	assertNotCreated(t, dest, "l1")
+}
+
+func TestExtractTarGzKeepsLinksInside(t *testing.T) {
+	dest, _ := sandbox(t)
+	archive := tarGz(t, []entry{
+		{name: "bin/", dir: true},
+		{name: "bin/tool", body: "tool"},
+		{name: "lib/", dir: true},
+		{name: "lib/tool", link: "../bin/tool"},
+		{name: "lib/hard", link: "bin/tool", hard: true},
+	})
+
+	err := ExtractTarGz(context.Background(), bytes.NewReader(archive), dest)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"lib/tool", "lib/hard"} {
+		got, err := ioutil.ReadFile(filepath.Join(dest, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "tool" {
+			t.Errorf("%s: got %q, want %q", name, got, "tool")
+		}
+	}
+}
+
+func assertNotCreated(t *testing.T, dest string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if _, err := os.Lstat(filepath.Join(dest, name)); !os.IsNotExist(err) {
+			t.Errorf("%s: got %v, want not created", name, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FallbackProvider is a Provider querying, in order, providers of the same
+// releases, such as a GitHub repository and its Gitea mirror, until one of
+// them answers. It tries the next provider only if the previous one failed
+// with a connection error or a 5xx status and, if NextOnNoRelease, with
+// ErrNoRelease; any other error is returned as is.
+type FallbackProvider struct {
+	// Providers are the providers, in order of preference.
+	Providers []Provider
+	// NextOnNoRelease makes a provider without releases (ErrNoRelease), such
+	// as a mirror not yet synchronized, a reason to try the next provider,
+	// instead of the answer.
+	NextOnNoRelease bool
+}
+
+// NewFallbackProvider returns a FallbackProvider querying providers in order.
+func NewFallbackProvider(providers ...Provider) *FallbackProvider {
+	return &FallbackProvider{Providers: providers}
+}
+
+// Latest returns the tag of the latest release of the first provider that
+// answers. If all of them fail, the error is a *ProvidersError.
+func (f *FallbackProvider) Latest(ctx context.Context) (string, error) {
+	providersErr := &ProvidersError{}
+	for i, p := range f.Providers {
+		tag, err := p.Latest(ctx)
+		if err == nil {
+			return tag, nil
+		}
+		next := failover(err) ||
+			f.NextOnNoRelease && errors.Is(err, ErrNoRelease)
+		if !next || ctx.Err() != nil {
+			return "", err
+		}
+		providersErr.Errors = append(providersErr.Errors,
+			fmt.Errorf("provider %d: %w", i, err))
+	}
+	if len(providersErr.Errors) == 0 {
+		return "", errors.New("fallback provider without providers")
+	}
+	return "", providersErr
+}
+
+// ProvidersError is returned by FallbackProvider when all the providers
+// failed. It wraps the error of the last provider.
+type ProvidersError struct {
+	// Errors are the errors of the providers, in order.
+	Errors []error
+}
+
+func (e *ProvidersError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return "all providers failed: " + strings.Join(msgs, "; ")
+}
+
+func (e *ProvidersError) Unwrap() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e.Errors[len(e.Errors)-1]
+}
@@ -0,0 +1,116 @@
+package release_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/marco-m/taschino/pkg/release"
+	"github.com/marco-m/taschino/pkg/release/releasetest"
+)
+
+// provider returns a GitHub provider of o/r served by a new server, with the
+// latest release tag or, if tag is empty, answering status.
+func provider(t *testing.T, tag string, status int) (release.Provider,
+	*releasetest.Server) {
+	t.Helper()
+	srv := releasetest.NewServer()
+	t.Cleanup(srv.Close)
+	if tag != "" {
+		srv.SetLatest("o", "r", release.Release{TagName: tag})
+	} else {
+		srv.Handle(releasetest.LatestPath("o", "r"), releasetest.Status(status))
+	}
+	return release.NewGitHub("o", "r", release.WithBaseURL(srv.URL)), srv
+}
+
+func TestFallbackProviderSkipsFailing(t *testing.T) {
+	failing, failingSrv := provider(t, "", http.StatusServiceUnavailable)
+	working, workingSrv := provider(t, "v1.2.3", 0)
+	unused, unusedSrv := provider(t, "v9.9.9", 0)
+
+	tag, err := release.NewFallbackProvider(failing, working, unused).
+		Latest(context.Background())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != "v1.2.3" {
+		t.Errorf("got %q, want v1.2.3", tag)
+	}
+	for _, tc := range []struct {
+		name string
+		srv  *releasetest.Server
+		want int
+	}{
+		{"failing", failingSrv, 1},
+		{"working", workingSrv, 1},
+		{"unused", unusedSrv, 0},
+	} {
+		if n := tc.srv.TotalRequests(); n != tc.want {
+			t.Errorf("%s provider: got %d requests, want %d", tc.name, n,
+				tc.want)
+		}
+	}
+}
+
+func TestFallbackProviderAllFail(t *testing.T) {
+	first, _ := provider(t, "", http.StatusInternalServerError)
+	second, _ := provider(t, "", http.StatusServiceUnavailable)
+
+	_, err := release.NewFallbackProvider(first, second).
+		Latest(context.Background())
+
+	var providersErr *release.ProvidersError
+	if !errors.As(err, &providersErr) {
+		t.Fatalf("got error %v, want a *ProvidersError", err)
+	}
+	if len(providersErr.Errors) != 2 {
+		t.Fatalf("got errors %v, want 2", providersErr.Errors)
+	}
+	for i, want := range []string{"provider 0: ", "provider 1: "} {
+		if msg := providersErr.Errors[i].Error(); !strings.HasPrefix(msg,
+			want) {
+			t.Errorf("error %d: got %q, want it to start with %q", i, msg,
+				want)
+		}
+	}
+	// It wraps the error of the last provider.
+	if !errors.Is(err, release.ErrUnavailable) {
+		t.Errorf("got error %v, want it to wrap ErrUnavailable", err)
+	}
+}
+
+func TestFallbackProviderNoRelease(t *testing.T) {
+	empty, _ := provider(t, "", http.StatusNotFound)
+	working, workingSrv := provider(t, "v1.2.3", 0)
+
+	_, err := release.NewFallbackProvider(empty, working).
+		Latest(context.Background())
+
+	// ErrNoRelease is an answer, not a failure.
+	if !errors.Is(err, release.ErrNoRelease) {
+		t.Errorf("got error %v, want ErrNoRelease", err)
+	}
+	if n := workingSrv.TotalRequests(); n != 0 {
+		t.Errorf("got %d requests to the next provider, want 0", n)
+	}
+
+	fallback := release.NewFallbackProvider(empty, working)
+	fallback.NextOnNoRelease = true
+	tag, err := fallback.Latest(context.Background())
+
+	if err != nil || tag != "v1.2.3" {
+		t.Errorf("with NextOnNoRelease: got tag %q, error %v; want v1.2.3", tag,
+			err)
+	}
+}
+
+func TestFallbackProviderWithoutProviders(t *testing.T) {
+	if _, err := release.NewFallbackProvider().Latest(
+		context.Background()); err == nil {
+		t.Error("got no error")
+	}
+}
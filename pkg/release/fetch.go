@@ -0,0 +1,298 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// authorizer adds token, in the form expected by a given provider, to req.
+type authorizer func(req *http.Request, token string)
+
+// bearerAuth is the authorizer for GitHub and for any API accepting an OAuth
+// bearer token.
+func bearerAuth(req *http.Request, token string) {
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
+// latestTag queries api_url, expecting a JSON object describing a release, and
+// returns its field tag_name.
+func latestTag(ctx context.Context, cfg *config, api_url string,
+	authorize authorizer) (string, error) {
+	release, err := getRelease(ctx, cfg, api_url, authorize, nil)
+	if err != nil {
+		return "", err
+	}
+	return release.TagName, nil
+}
+
+// getRelease queries api_url, adding header to the request, expecting a JSON
+// object describing a release.
+func getRelease(ctx context.Context, cfg *config, api_url string,
+	authorize authorizer, header http.Header) (*Release, error) {
+	var release Release
+	_, err := getJSON(ctx, cfg, api_url, authorize, header, &release)
+	if err != nil {
+		return nil, err
+	}
+	if release.TagName == "" {
+		return nil,
+			fmt.Errorf("parsing JSON response: missing 'field tag_name'")
+	}
+	return &release, nil
+}
+
+// getJSON queries api_url, adding header to the request, and decodes the JSON
+// response into v, retrying as configured. It returns the header of the
+// response. On status 304 Not Modified, v is untouched and the error is
+// errNotModified.
+func getJSON(ctx context.Context, cfg *config, api_url string,
+	authorize authorizer, header http.Header, v interface{}) (http.Header, error) {
+	for attempt := 1; ; attempt++ {
+		respHeader, err := getJSONOnce(ctx, cfg, api_url, authorize, header, v)
+		if err == nil || err == errNotModified {
+			return respHeader, err
+		}
+		wait, ok := cfg.retryDelay(attempt, err)
+		if !ok || ctx.Err() != nil {
+			return nil, err
+		}
+		cfg.debug("retrying request", "url", api_url, "attempt", attempt,
+			"wait", wait, "error", err)
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// getJSONOnce is a single attempt of getJSON.
+func getJSONOnce(ctx context.Context, cfg *config, api_url string,
+	authorize authorizer, header http.Header, v interface{}) (http.Header, error) {
+	ctx, cancel := cfg.withTimeout(ctx)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, api_url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create http request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("User-Agent", cfg.userAgent)
+	if cfg.token != "" {
+		authorize(req, cfg.token)
+	}
+	cfg.debug("request", "method", req.Method, "url", api_url)
+	resp, err := cfg.client.Do(req)
+	if err != nil {
+		cfg.debug("request failed", "url", api_url, "error", err)
+		return nil, fmt.Errorf("http client Do: %w", err)
+	}
+	defer resp.Body.Close()
+	if obs := observed(ctx); obs != nil {
+		obs.statusCode = resp.StatusCode
+	}
+	cfg.debug("response", "url", api_url, "status", resp.StatusCode,
+		"ratelimit-remaining", resp.Header.Get("X-RateLimit-Remaining"),
+		"ratelimit-reset", resp.Header.Get("X-RateLimit-Reset"))
+	if resp.StatusCode == http.StatusNotModified {
+		return resp.Header, errNotModified
+	}
+	if resp.Request != nil && resp.Request.URL.String() != api_url {
+		final := resp.Request.URL.String()
+		// Such as a renamed repository: report where the answer comes from.
+		cfg.warn("request redirected", "url", api_url, "location", final)
+		api_url = final
+	}
+	if err := checkStatus(cfg, resp, api_url); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newAPIError(resp, api_url,
+			fmt.Errorf("unexpected status %s at %s", resp.Status, api_url))
+	}
+
+	var body io.Reader = resp.Body
+	var limited *io.LimitedReader
+	if n := cfg.maxResponse; n > 0 && n < math.MaxInt64 {
+		// One byte more than the limit, to tell a body of the limit size from
+		// a bigger one.
+		limited = &io.LimitedReader{R: resp.Body, N: n + 1}
+		body = limited
+	}
+	// tooLarge returns the error to report instead of a JSON error, if any:
+	// the JSON is truncated because the body exceeds the limit.
+	tooLarge := func() error {
+		if limited == nil || limited.N > 0 {
+			return nil
+		}
+		return fmt.Errorf("%w at %s (limit %d bytes)", ErrResponseTooLarge,
+			api_url, cfg.maxResponse)
+	}
+	decoder := json.NewDecoder(body)
+	if cfg.strictJSON {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(v); err != nil {
+		if err := tooLarge(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("parsing JSON response: %w", err)
+	}
+	if cfg.strictJSON {
+		// The body must be a single JSON value, nothing else.
+		if _, err := decoder.Token(); err != io.EOF {
+			if err := tooLarge(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("parsing JSON response: " +
+				"unexpected data after the JSON value")
+		}
+	}
+	return resp.Header, nil
+}
+
+// errNotModified is returned by getJSON on status 304 Not Modified.
+var errNotModified = errors.New("not modified")
+
+// checkStatus returns an *APIError, wrapping one of the sentinel errors of
+// this package, when the status code of resp denotes a known failure.
+func checkStatus(cfg *config, resp *http.Response, api_url string) error {
+	if err := statusError(cfg, resp, api_url); err != nil {
+		return newAPIError(resp, api_url, err)
+	}
+	return nil
+}
+
+// statusError is the error of checkStatus, before wrapping.
+func statusError(cfg *config, resp *http.Response, api_url string) error {
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		if cfg.token == "" {
+			return fmt.Errorf("%w at %s "+
+				"(if the repository is private, a token is required)",
+				ErrNoRelease, api_url)
+		}
+		return fmt.Errorf("%w at %s", ErrNoRelease, api_url)
+	case http.StatusTooManyRequests:
+		return rateLimitError(resp, api_url)
+	case http.StatusForbidden:
+		msg := apiMessage(resp)
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" ||
+			resp.Header.Get("Retry-After") != "" || isSecondaryRateLimit(msg) {
+			err := rateLimitError(resp, api_url)
+			err.Message = msg
+			err.Secondary = isSecondaryRateLimit(msg)
+			return err
+		}
+		if msg != "" {
+			return fmt.Errorf("%w at %s (status %s: %s)", ErrUnauthorized,
+				api_url, resp.Status, msg)
+		}
+		return fmt.Errorf("%w at %s (status %s)", ErrUnauthorized, api_url,
+			resp.Status)
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w at %s (status %s)", ErrUnauthorized, api_url,
+			resp.Status)
+	case http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return fmt.Errorf("%w at %s (status %s)", ErrUnavailable, api_url,
+			resp.Status)
+	}
+	return nil
+}
+
+// newAPIError returns the *APIError for resp, wrapping err.
+func newAPIError(resp *http.Response, api_url string, err error) *APIError {
+	apiErr := &APIError{
+		URL:                api_url,
+		StatusCode:         resp.StatusCode,
+		RateLimitRemaining: -1,
+		RateLimitReset:     rateLimitReset(resp),
+		Err:                err,
+	}
+	remaining, parseErr := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if parseErr == nil {
+		apiErr.RateLimitRemaining = remaining
+	}
+	return apiErr
+}
+
+// maxMessageSize is the maximum size of the body of an error response read by
+// apiMessage.
+const maxMessageSize = 64 << 10
+
+// apiMessage returns the "message" field of the JSON body of the error
+// response resp, such as "API rate limit exceeded for ...", or the empty string
+// if there is none.
+func apiMessage(resp *http.Response) string {
+	var body struct {
+		Message string `json:"message"`
+	}
+	err := json.NewDecoder(io.LimitReader(resp.Body, maxMessageSize)).
+		Decode(&body)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(body.Message)
+}
+
+// isSecondaryRateLimit reports whether msg, the message of a 403 response,
+// denotes a secondary rate limit, formerly called abuse detection.
+// https://docs.github.com/en/rest/using-the-rest-api/rate-limits-for-the-rest-api#about-secondary-rate-limits
+func isSecondaryRateLimit(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "secondary rate limit") ||
+		strings.Contains(msg, "abuse detection")
+}
+
+// rateLimitError returns the RateLimitError corresponding to resp.
+func rateLimitError(resp *http.Response, api_url string) *RateLimitError {
+	return &RateLimitError{
+		URL:        api_url,
+		Reset:      rateLimitReset(resp),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// rateLimitReset returns the time of the header X-RateLimit-Reset of resp, or
+// the zero time if missing or invalid.
+func rateLimitReset(resp *http.Response) time.Time {
+	// The header is the reset time in UTC epoch seconds.
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(reset, 0)
+}
+
+// nextLink returns the URL with relation "next" in the Link header of a
+// paginated response, or the empty string if this is the last page.
+// Example of header:
+//
+//	<https://api.github.com/repositories/1/releases?page=2>; rel="next",
+//	<https://api.github.com/repositories/1/releases?page=5>; rel="last"
+func nextLink(header http.Header) string {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, param := range parts[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return target[1 : len(target)-1]
+			}
+		}
+	}
+	return ""
+}
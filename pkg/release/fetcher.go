@@ -0,0 +1,82 @@
+package release
+
+import "sync"
+
+// Fetcher looks up the latest release of a repository. Depend on it instead of
+// calling GitHubLatest directly, to be able to inject a fake in tests.
+type Fetcher interface {
+	// Latest returns the tag of the latest release of owner/repo.
+	Latest(owner string, repo string) (string, error)
+}
+
+// GitHubFetcher is the Fetcher backed by GitHubLatest.
+type GitHubFetcher struct {
+	opts []Option
+}
+
+// NewGitHubFetcher returns a Fetcher calling GitHubLatest with opts.
+func NewGitHubFetcher(opts ...Option) *GitHubFetcher {
+	return &GitHubFetcher{opts: opts}
+}
+
+// Latest returns GitHubLatest(owner, repo).
+func (f *GitHubFetcher) Latest(owner string, repo string) (string, error) {
+	return GitHubLatest(owner, repo, f.opts...)
+}
+
+// FakeFetcher is a Fetcher for tests, returning a canned tag or error for any
+// repository.
+type FakeFetcher struct {
+	Tag string
+	Err error
+}
+
+// Latest returns f.Tag, or f.Err if not nil.
+func (f FakeFetcher) Latest(owner string, repo string) (string, error) {
+	if f.Err != nil {
+		return "", f.Err
+	}
+	return f.Tag, nil
+}
+
+// RecordingFetcher is a Fetcher for tests, returning a canned tag or error per
+// repository and recording the repositories it was asked about, in order, to
+// be checked with Calls. It is safe for concurrent use, provided Responses and
+// Default are not modified after the first call.
+type RecordingFetcher struct {
+	// Responses are the responses for each repository.
+	Responses map[RepoRef]FakeFetcher
+	// Default is the response for a repository not in Responses.
+	Default FakeFetcher
+
+	mu    sync.Mutex
+	calls []RepoRef
+}
+
+// Latest records owner/repo and returns its response.
+func (f *RecordingFetcher) Latest(owner string, repo string) (string, error) {
+	ref := RepoRef{Owner: owner, Repo: repo}
+	f.mu.Lock()
+	f.calls = append(f.calls, ref)
+	f.mu.Unlock()
+	if response, ok := f.Responses[ref]; ok {
+		return response.Latest(owner, repo)
+	}
+	return f.Default.Latest(owner, repo)
+}
+
+// Calls returns the repositories passed to Latest so far, in order, one per
+// call.
+func (f *RecordingFetcher) Calls() []RepoRef {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := make([]RepoRef, len(f.calls))
+	copy(calls, f.calls)
+	return calls
+}
+
+var (
+	_ Fetcher = (*GitHubFetcher)(nil)
+	_ Fetcher = FakeFetcher{}
+	_ Fetcher = (*RecordingFetcher)(nil)
+)
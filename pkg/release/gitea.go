@@ -0,0 +1,64 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GiteaLatest queries the releases API of the Gitea instance at baseURL (for
+// example "https://gitea.mycorp.com") and returns the tag of the latest
+// release as an opaque string (that is, it might be or not a valid semver
+// string).
+//
+// Since there is no canonical Gitea host, baseURL is mandatory and takes
+// precedence over WithBaseURL.
+func GiteaLatest(baseURL string, owner string, repo string,
+	opts ...Option) (string, error) {
+	return GiteaLatestContext(context.Background(), baseURL, owner, repo,
+		opts...)
+}
+
+// GiteaLatestContext is like GiteaLatest, but the request is bound to ctx.
+func GiteaLatestContext(ctx context.Context, baseURL string, owner string,
+	repo string, opts ...Option) (string, error) {
+	return NewGitea(baseURL, owner, repo, opts...).Latest(ctx)
+}
+
+// Gitea is the Provider of the releases of a repository of a Gitea instance.
+type Gitea struct {
+	baseURL string
+	owner   string
+	repo    string
+	cfg     *config
+}
+
+// NewGitea returns the Provider of the releases of the repository owner/repo
+// of the Gitea instance at baseURL.
+func NewGitea(baseURL string, owner string, repo string,
+	opts ...Option) *Gitea {
+	return &Gitea{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		owner:   owner,
+		repo:    repo,
+		cfg:     newConfig(opts),
+	}
+}
+
+// Latest returns the tag of the latest release.
+func (gt *Gitea) Latest(ctx context.Context) (string, error) {
+	// https://gitea.com/api/swagger#/repository/repoGetLatestRelease
+	// API: GET /api/v1/repos/:owner/:repo/releases/latest
+	api_url := fmt.Sprintf("%s/api/v1/repos/%s/%s/releases/latest",
+		gt.baseURL, gt.owner, gt.repo)
+
+	return gt.cfg.observe(ctx, gt.owner, gt.repo,
+		func(ctx context.Context) (string, error) {
+			return latestTag(ctx, gt.cfg, api_url, giteaAuth)
+		})
+}
+
+func giteaAuth(req *http.Request, token string) {
+	req.Header.Set("Authorization", "token "+token)
+}
@@ -0,0 +1,66 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// gitLabAPI is the base URL of the public GitLab API.
+const gitLabAPI = "https://gitlab.com/api/v4"
+
+// GitLabLatest queries the GitLab releases API and returns the tag of the
+// latest release as an opaque string (that is, it might be or not a valid
+// semver string).
+//
+// projectID is either the numeric ID of the project or its path, such as
+// "group/project", plain or already URL-encoded. The token of WithToken is
+// sent as a GitLab PRIVATE-TOKEN; the base URL of WithBaseURL is the API root,
+// such as "https://gitlab.mycorp.com/api/v4".
+func GitLabLatest(projectID string, opts ...Option) (string, error) {
+	return GitLabLatestContext(context.Background(), projectID, opts...)
+}
+
+// GitLabLatestContext is like GitLabLatest, but the request is bound to ctx.
+func GitLabLatestContext(ctx context.Context, projectID string,
+	opts ...Option) (string, error) {
+	return NewGitLab(projectID, opts...).Latest(ctx)
+}
+
+// GitLab is the Provider of the releases of a GitLab project.
+type GitLab struct {
+	projectID string
+	cfg       *config
+}
+
+// NewGitLab returns the Provider of the releases of the GitLab project
+// projectID. See GitLabLatest for the meaning of projectID.
+func NewGitLab(projectID string, opts ...Option) *GitLab {
+	return &GitLab{projectID: projectID, cfg: newConfig(opts)}
+}
+
+// Latest returns the tag of the latest release.
+func (gl *GitLab) Latest(ctx context.Context) (string, error) {
+	// https://docs.gitlab.com/ee/api/releases/#get-the-latest-release
+	// API: GET /projects/:id/releases/permalink/latest
+	api_url := fmt.Sprintf("%s/projects/%s/releases/permalink/latest",
+		gl.cfg.apiURL(gitLabAPI), escapeProjectID(gl.projectID))
+
+	return gl.cfg.observe(ctx, "", gl.projectID,
+		func(ctx context.Context) (string, error) {
+			return latestTag(ctx, gl.cfg, api_url, gitLabAuth)
+		})
+}
+
+// escapeProjectID URL-encodes projectID, unless it is already encoded.
+func escapeProjectID(projectID string) string {
+	if unescaped, err := url.PathUnescape(projectID); err == nil {
+		projectID = unescaped
+	}
+	return url.PathEscape(projectID)
+}
+
+func gitLabAuth(req *http.Request, token string) {
+	req.Header.Set("PRIVATE-TOKEN", token)
+}
@@ -0,0 +1,67 @@
+package release
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// gzipTransport asks for gzip-compressed responses and decompresses them
+// transparently, whatever the wrapped transport. http.Transport already does
+// so on its own, but not if DisableCompression is set, and other
+// RoundTrippers, such as test doubles or instrumented transports, usually do
+// not. As http.Transport, it leaves alone requests that set Accept-Encoding
+// themselves or that ask for a byte range, whose offsets would refer to the
+// compressed body.
+type gzipTransport struct {
+	base http.RoundTripper
+}
+
+func (t *gzipTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Encoding") != "" || req.Header.Get("Range") != "" ||
+		req.Method == http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+	// A RoundTripper must not modify the request.
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return resp, nil
+	}
+	resp.Body = &gzipReader{body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// gzipReader decompresses body, reading the gzip header only at the first
+// Read, so that an empty body of an error response does not fail early.
+type gzipReader struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (gr *gzipReader) Read(p []byte) (int, error) {
+	if gr.err != nil {
+		return 0, gr.err
+	}
+	if gr.zr == nil {
+		gr.zr, gr.err = gzip.NewReader(gr.body)
+		if gr.err != nil {
+			return 0, gr.err
+		}
+	}
+	return gr.zr.Read(p)
+}
+
+func (gr *gzipReader) Close() error {
+	return gr.body.Close()
+}
@@ -0,0 +1,165 @@
+package release
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// gzipped returns s compressed with gzip.
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// roundTripFunc is an http.RoundTripper calling itself, which, unlike
+// http.Transport, does not handle compression on its own.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// gzipResponse returns a response to req with body compressed with gzip.
+func gzipResponse(t *testing.T, req *http.Request, body string) *http.Response {
+	t.Helper()
+	z := gzipped(t, body)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Encoding": {"gzip"},
+			"Content-Type":     {"application/json"},
+		},
+		Body:          ioutil.NopCloser(bytes.NewReader(z)),
+		ContentLength: int64(len(z)),
+		Request:       req,
+	}
+}
+
+func TestListReleasesRequestsAndDecodesGzip(t *testing.T) {
+	const body = `[{"tag_name":"v1.2.3","body":"notes"},{"tag_name":"v1.2.2"}]`
+	var acceptEncoding string
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			acceptEncoding = r.Header.Get("Accept-Encoding")
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(gzipped(t, body))
+		}))
+	defer srv.Close()
+	for _, tc := range []struct {
+		name      string
+		transport http.RoundTripper
+	}{
+		// Without compression of its own, so that it is gzipTransport
+		// that asks for gzip.
+		{"http.Transport", &http.Transport{DisableCompression: true}},
+		{"RoundTripper", roundTripFunc(func(req *http.Request) (*http.Response,
+			error) {
+			acceptEncoding = req.Header.Get("Accept-Encoding")
+			return gzipResponse(t, req, body), nil
+		})},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			acceptEncoding = ""
+
+			releases, err := NewGitHub("o", "r", WithBaseURL(srv.URL),
+				WithTransport(tc.transport)).ListReleases(context.Background())
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if acceptEncoding != "gzip" {
+				t.Errorf("Accept-Encoding: got %q, want gzip", acceptEncoding)
+			}
+			if len(releases) != 2 || releases[0].TagName != "v1.2.3" ||
+				releases[0].Body != "notes" {
+				t.Errorf("got %+v, want v1.2.3 and v1.2.2", releases)
+			}
+		})
+	}
+}
+
+func TestGzipTransportLeavesAlone(t *testing.T) {
+	const body = "compressed"
+	for _, tc := range []struct {
+		name   string
+		header string
+		value  string
+	}{
+		{"Accept-Encoding set by the caller", "Accept-Encoding", "gzip"},
+		{"byte range", "Range", "bytes=0-99"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := &gzipTransport{base: roundTripFunc(
+				func(req *http.Request) (*http.Response, error) {
+					if got := req.Header.Get(tc.header); got != tc.value {
+						t.Errorf("%s: got %q, want %q", tc.header, got, tc.value)
+					}
+					return gzipResponse(t, req, body), nil
+				})}
+			req := httptest.NewRequest("GET", "http://example.test/a", nil)
+			req.Header.Set(tc.header, tc.value)
+
+			resp, err := transport.RoundTrip(req)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			got, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, gzipped(t, body)) {
+				t.Errorf("body: got %q, want it still compressed", got)
+			}
+			if resp.Header.Get("Content-Encoding") != "gzip" {
+				t.Error("Content-Encoding removed")
+			}
+		})
+	}
+}
+
+func TestGzipTransportDoesNotModifyRequest(t *testing.T) {
+	transport := &gzipTransport{base: roundTripFunc(
+		func(req *http.Request) (*http.Response, error) {
+			return gzipResponse(t, req, "decoded"), nil
+		})}
+	req := httptest.NewRequest("GET", "http://example.test/a", nil)
+
+	resp, err := transport.RoundTrip(req)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	got, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "decoded" {
+		t.Errorf("body: got %q, want %q", got, "decoded")
+	}
+	if enc := req.Header.Get("Accept-Encoding"); enc != "" {
+		t.Errorf("request of the caller modified: Accept-Encoding %q", enc)
+	}
+	if !resp.Uncompressed || resp.ContentLength != -1 ||
+		strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		t.Errorf("got Uncompressed %v, ContentLength %d, Content-Encoding %q",
+			resp.Uncompressed, resp.ContentLength,
+			resp.Header.Get("Content-Encoding"))
+	}
+}
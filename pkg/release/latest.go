@@ -0,0 +1,239 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"golang.org/x/mod/semver"
+)
+
+// LatestIncludingPrereleases is like GitHubLatest, but considers also
+// prereleases: it lists the releases of owner/repo, skips drafts and tags that
+// are not valid semver, and returns the highest tag by semver precedence (so
+// v1.2.0 > v1.2.0-rc.1 > v1.1.0).
+func LatestIncludingPrereleases(owner string, repo string,
+	opts ...Option) (string, error) {
+	return latestFunc(context.Background(), NewGitHub(owner, repo, opts...),
+		func(Release) bool { return true }, ErrNoRelease)
+}
+
+// LatestStable lists the releases of owner/repo and returns the highest tag by
+// semver precedence, skipping drafts, prereleases and tags that are not valid
+// semver. Contrary to GitHubLatest, the returned tag is always valid semver.
+// If there is no stable release, the error wraps ErrNoStableRelease.
+func LatestStable(owner string, repo string, opts ...Option) (string, error) {
+	return latestFunc(context.Background(), NewGitHub(owner, repo, opts...),
+		isStable, ErrNoStableRelease)
+}
+
+// LatestPrerelease lists the releases of owner/repo and returns the highest tag
+// by semver precedence among the prereleases (tags with a semver prerelease
+// part, such as v1.3.0-rc.1), even if a newer stable release exists, skipping
+// drafts and tags that are not valid semver. If there is no prerelease, the
+// error wraps ErrNoPrerelease.
+func LatestPrerelease(owner string, repo string, opts ...Option) (string, error) {
+	return latestFunc(context.Background(), NewGitHub(owner, repo, opts...),
+		func(r Release) bool { return !isStable(r) }, ErrNoPrerelease)
+}
+
+// LatestWithinMajor lists the releases of owner/repo and returns the highest
+// tag with major version major, such as the highest v1.x.y for major 1,
+// skipping drafts, prereleases (unless WithPrereleases is used) and tags that
+// are not valid semver. If there is no such release, the error wraps
+// ErrNoRelease.
+func LatestWithinMajor(owner string, repo string, major int,
+	opts ...Option) (string, error) {
+	gh := NewGitHub(owner, repo, opts...)
+	want := fmt.Sprintf("v%d", major)
+	return latestFunc(context.Background(), gh,
+		func(r Release) bool {
+			return semver.Major(r.TagName) == want &&
+				(gh.cfg.prereleases || isStable(r))
+		}, ErrNoRelease)
+}
+
+// LatestInstallable lists the releases of owner/repo and returns the highest
+// release newer than currentV with an asset for the platform goos/goarch, as
+// selected by SelectAsset, skipping drafts, prereleases (unless
+// WithPrereleases is used) and tags that are not valid semver. A release
+// whose binaries are not uploaded yet is thus skipped in favor of an older
+// one. As IsNewer, an empty currentV is older than any release. If there is no
+// such release, the error wraps ErrNoInstallableUpdate.
+func LatestInstallable(owner string, repo string, currentV string, goos string,
+	goarch string, opts ...Option) (*Release, error) {
+	var current string
+	if currentV != "" {
+		var err error
+		if current, err = validVersion(currentV, "installed"); err != nil {
+			return nil, err
+		}
+	}
+	gh := NewGitHub(owner, repo, opts...)
+	return latestReleaseFunc(context.Background(), gh,
+		func(r Release) bool {
+			if current != "" && semver.Compare(r.TagName, current) <= 0 {
+				return false
+			}
+			if !gh.cfg.prereleases && !isStable(r) {
+				return false
+			}
+			_, err := gh.cfg.selectAsset(&r, goos, goarch)
+			return err == nil
+		}, ErrNoInstallableUpdate)
+}
+
+// LatestFunc lists the releases of owner/repo and returns the highest tag by
+// semver precedence among the releases for which accept returns true, for
+// rules not covered by the other functions, skipping drafts and tags that are
+// not valid semver. For example, to require a stable release with a checksums
+// file:
+//
+//	release.LatestFunc("owner", "repo", func(r release.Release) bool {
+//		_, err := r.Asset("checksums.txt")
+//		return !r.Prerelease && err == nil
+//	})
+//
+// accept sees the tag as a version, without the prefix of WithTagPrefix and
+// with the leading "v"; the returned tag is unchanged. If no release is
+// accepted, the error wraps ErrNoRelease.
+func LatestFunc(owner string, repo string, accept func(Release) bool,
+	opts ...Option) (string, error) {
+	return NewGitHub(owner, repo, opts...).LatestFunc(context.Background(),
+		accept)
+}
+
+// LatestFunc returns the highest tag among the releases accepted by accept.
+// See the function LatestFunc.
+func (gh *GitHub) LatestFunc(ctx context.Context,
+	accept func(Release) bool) (string, error) {
+	return latestFunc(ctx, gh, accept, ErrNoRelease)
+}
+
+// LatestMatchingPattern lists the releases of owner/repo and returns the
+// highest tag matching re, for repositories tagging per component, such as
+// "frontend-v1.2.0" and "backend-v0.9.1". The version of a tag is:
+//   - if re has a capturing group, the text of the first group, such as for
+//     `^frontend-(v[0-9.]+)$`;
+//   - or else the version at the end of the match, that is its longest suffix
+//     that is a valid semver, such as for `^frontend-v[0-9.]+$`;
+//   - or else, if the match has no version, the part of the tag after the
+//     match, for a pattern matching the prefix such as `^frontend-`.
+//
+// The leading "v" of the version is optional. It skips drafts, prereleases
+// (unless WithPrereleases is used) and versions that are not valid semver; the
+// returned tag is the original one, with its prefix. If no tag matches or no
+// matching tag has a valid version, the error wraps ErrNoRelease.
+func LatestMatchingPattern(owner string, repo string, re *regexp.Regexp,
+	opts ...Option) (string, error) {
+	gh := NewGitHub(owner, repo, opts...)
+	releases, err := gh.ListReleases(context.Background())
+	if err != nil {
+		return "", err
+	}
+	published := FilterDrafts(releases)
+	versions := make([]Release, len(published))
+	matched := 0
+	for i, r := range published {
+		v, ok := patternVersion(re, r.TagName)
+		if ok {
+			matched++
+		}
+		// An empty version is not valid: highest skips it.
+		r.TagName = ""
+		if ok && v != "" {
+			r.TagName = normalize(v)
+		}
+		versions[i] = r
+	}
+	if matched == 0 {
+		return "", fmt.Errorf("%w for %s/%s: no tag matches %s among %d releases",
+			ErrNoRelease, owner, repo, re, len(releases))
+	}
+	best := highest(versions, func(r Release) bool {
+		return gh.cfg.prereleases || isStable(r)
+	})
+	if best < 0 {
+		return "", fmt.Errorf("%w for %s/%s: none of the %d tags matching %s "+
+			"has a valid version", ErrNoRelease, owner, repo, matched, re)
+	}
+	return published[best].TagName, nil
+}
+
+// patternVersion returns the version of tag for LatestMatchingPattern, and
+// false if tag does not match re.
+func patternVersion(re *regexp.Regexp, tag string) (string, bool) {
+	loc := re.FindStringSubmatchIndex(tag)
+	if loc == nil {
+		return "", false
+	}
+	if re.NumSubexp() > 0 {
+		if loc[2] < 0 {
+			// The group did not participate in the match.
+			return "", true
+		}
+		return tag[loc[2]:loc[3]], true
+	}
+	match := tag[loc[0]:loc[1]]
+	for i := range match {
+		if semver.IsValid(normalize(match[i:])) {
+			return match[i:], true
+		}
+	}
+	return tag[loc[1]:], true
+}
+
+// isStable reports whether the tag of r has no semver prerelease part.
+func isStable(r Release) bool {
+	return semver.Prerelease(r.TagName) == ""
+}
+
+// latestFunc lists the releases of gh and returns the highest valid semver tag
+// among the releases accepted by accept. Drafts are always skipped. If no
+// release is accepted, the error wraps notFound.
+func latestFunc(ctx context.Context, gh *GitHub, accept func(Release) bool,
+	notFound error) (string, error) {
+	release, err := latestReleaseFunc(ctx, gh, accept, notFound)
+	if err != nil {
+		return "", err
+	}
+	return release.TagName, nil
+}
+
+// latestReleaseFunc is like latestFunc, but returns the whole release.
+// accept sees the releases with the tag as a version: without the prefix of
+// WithTagPrefix and with the leading "v"; the returned release is unchanged.
+func latestReleaseFunc(ctx context.Context, gh *GitHub,
+	accept func(Release) bool, notFound error) (*Release, error) {
+	releases, err := gh.ListReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	published := FilterDrafts(releases)
+	versions := make([]Release, len(published))
+	for i, r := range published {
+		r.TagName = normalize(gh.cfg.tagVersion(r.TagName))
+		versions[i] = r
+	}
+	best := highest(versions, accept)
+	if best < 0 {
+		return nil, fmt.Errorf("%w for %s/%s among %d releases",
+			notFound, gh.owner, gh.repo, len(releases))
+	}
+	return &published[best], nil
+}
+
+// highest returns the index of the release with the highest valid semver tag
+// among the releases accepted by accept, or -1 if there is none.
+func highest(releases []Release, accept func(Release) bool) int {
+	best := -1
+	for i, r := range releases {
+		if !semver.IsValid(r.TagName) || !accept(r) {
+			continue
+		}
+		if best < 0 || semver.Compare(r.TagName, releases[best].TagName) > 0 {
+			best = i
+		}
+	}
+	return best
+}
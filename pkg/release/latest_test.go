@@ -0,0 +1,86 @@
+package release_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/marco-m/taschino/pkg/release"
+	"github.com/marco-m/taschino/pkg/release/releasetest"
+)
+
+func TestLatestMatchingPattern(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	srv.SetReleases("o", "r", []release.Release{
+		{TagName: "backend-v2.0.0"},
+		{TagName: "frontend-v1.10.0-rc.1"},
+		{TagName: "frontend-v1.9.0"},
+		{TagName: "frontend-v1.2.0"},
+		{TagName: "frontend-1.3.0"},
+	})
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{`^frontend-`, "frontend-v1.9.0"},
+		{`^frontend-(v[0-9.]+)$`, "frontend-v1.9.0"},
+		{`^frontend-v[0-9.]+$`, "frontend-v1.9.0"},
+		{`^frontend-[0-9.]+$`, "frontend-1.3.0"},
+		{`^backend-v`, "backend-v2.0.0"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.pattern, func(t *testing.T) {
+			got, err := release.LatestMatchingPattern("o", "r",
+				regexp.MustCompile(tc.pattern), release.WithBaseURL(srv.URL))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLatestMatchingPatternPrereleases(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	srv.SetReleases("o", "r", []release.Release{
+		{TagName: "frontend-v1.10.0-rc.1"},
+		{TagName: "frontend-v1.9.0"},
+	})
+	re := regexp.MustCompile(`^frontend-v.*$`)
+
+	stable, err := release.LatestMatchingPattern("o", "r", re,
+		release.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pre, err := release.LatestMatchingPattern("o", "r", re,
+		release.WithBaseURL(srv.URL), release.WithPrereleases())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "frontend-v1.9.0"; stable != want {
+		t.Errorf("stable: got %q, want %q", stable, want)
+	}
+	if want := "frontend-v1.10.0-rc.1"; pre != want {
+		t.Errorf("with prereleases: got %q, want %q", pre, want)
+	}
+}
+
+func TestLatestMatchingPatternNoMatch(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	srv.SetReleases("o", "r", []release.Release{{TagName: "backend-v2.0.0"}})
+
+	_, err := release.LatestMatchingPattern("o", "r",
+		regexp.MustCompile(`^frontend-`), release.WithBaseURL(srv.URL))
+
+	if !errors.Is(err, release.ErrNoRelease) {
+		t.Fatalf("got error %v, want ErrNoRelease", err)
+	}
+}
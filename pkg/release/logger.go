@@ -0,0 +1,24 @@
+package release
+
+// Logger receives the diagnostic logs of this package. It is the subset of
+// the methods of *slog.Logger used by this package, so that a *slog.Logger can
+// be passed to WithLogger while this package keeps supporting Go versions
+// older than log/slog.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+}
+
+// debug logs msg at debug level, if there is a logger.
+func (cfg *config) debug(msg string, args ...interface{}) {
+	if cfg.logger != nil {
+		cfg.logger.Debug(msg, args...)
+	}
+}
+
+// warn logs msg at warning level, if there is a logger.
+func (cfg *config) warn(msg string, args ...interface{}) {
+	if cfg.logger != nil {
+		cfg.logger.Warn(msg, args...)
+	}
+}
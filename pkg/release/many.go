@@ -0,0 +1,79 @@
+package release
+
+import (
+	"context"
+	"sync"
+)
+
+// RepoRef identifies a GitHub repository.
+type RepoRef struct {
+	Owner string
+	Repo  string
+}
+
+func (r RepoRef) String() string {
+	return r.Owner + "/" + r.Repo
+}
+
+// Result is the result of the lookup of the latest release of a repository.
+type Result struct {
+	// Tag is the tag of the latest release. Empty if Err is set.
+	Tag string
+	// Err is the error of the lookup, if any.
+	Err error
+}
+
+// defaultConcurrency is the number of concurrent requests of LatestMany when
+// WithConcurrency is not used.
+const defaultConcurrency = 4
+
+// LatestMany looks up concurrently the latest release of each of repos, as
+// GitHubLatest does, and returns the result of each. Use WithConcurrency to
+// bound the number of concurrent requests. For a global timeout, pass a ctx
+// with a deadline: the lookups not yet done when ctx expires have the error of
+// ctx as result.
+func LatestMany(ctx context.Context, repos []RepoRef,
+	opts ...Option) map[RepoRef]Result {
+	concurrency := newConfig(opts).concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	type item struct {
+		ref    RepoRef
+		result Result
+	}
+	work := make(chan RepoRef)
+	results := make(chan item)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ref := range work {
+				var result Result
+				if err := ctx.Err(); err != nil {
+					result.Err = err
+				} else {
+					result.Tag, result.Err = GitHubLatestContext(ctx, ref.Owner,
+						ref.Repo, opts...)
+				}
+				results <- item{ref, result}
+			}
+		}()
+	}
+	go func() {
+		for _, ref := range repos {
+			work <- ref
+		}
+		close(work)
+		wg.Wait()
+		close(results)
+	}()
+
+	collected := make(map[RepoRef]Result, len(repos))
+	for it := range results {
+		collected[it.ref] = it.result
+	}
+	return collected
+}
@@ -0,0 +1,116 @@
+package release
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CachingProvider is a Provider that memoizes in memory, for TTL, the result
+// of another Provider. It is safe for concurrent use: concurrent calls to
+// Latest while the value is being fetched share the same request. Errors are
+// not memoized.
+//
+// Configure the fields before the first call to Latest and do not change them
+// afterwards. Use one CachingProvider per repository.
+type CachingProvider struct {
+	// Provider is the wrapped Provider.
+	Provider Provider
+	// TTL is how long a fetched value is considered fresh.
+	TTL time.Duration
+	// StaleWhileRevalidate makes Latest return immediately an expired value,
+	// while refreshing it in the background, so that callers never block on a
+	// refresh (except for the very first fetch).
+	StaleWhileRevalidate bool
+
+	mu        sync.Mutex
+	tag       string
+	fetchedAt time.Time
+	valid     bool
+	inflight  *call
+	// generation counts the calls to Invalidate, so that a fetch started
+	// before one does not store its, maybe outdated, value.
+	generation uint64
+}
+
+// call is a fetch in progress. Once done is closed, tag and err are set.
+type call struct {
+	done chan struct{}
+	tag  string
+	err  error
+}
+
+// NewCachingProvider returns a CachingProvider memoizing p for ttl.
+func NewCachingProvider(p Provider, ttl time.Duration) *CachingProvider {
+	return &CachingProvider{Provider: p, TTL: ttl}
+}
+
+// Latest returns the memoized tag of the latest release, fetching it from the
+// wrapped Provider when missing or expired. Canceling ctx stops waiting for
+// the fetch, but not the fetch itself, which is shared with other callers.
+func (c *CachingProvider) Latest(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	if c.valid && time.Since(c.fetchedAt) < c.TTL {
+		tag := c.tag
+		c.mu.Unlock()
+		return tag, nil
+	}
+	if c.valid && c.StaleWhileRevalidate {
+		tag := c.tag
+		if c.inflight == nil {
+			c.startLocked()
+		}
+		c.mu.Unlock()
+		return tag, nil
+	}
+	cl := c.inflight
+	if cl == nil {
+		cl = c.startLocked()
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-cl.done:
+		return cl.tag, cl.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Invalidate discards the memoized value, so that the next call to Latest
+// fetches it again. A fetch in progress is not memoized; the calls already
+// waiting for it still get its result.
+func (c *CachingProvider) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.valid = false
+	c.tag = ""
+	c.inflight = nil
+	c.generation++
+}
+
+// startLocked starts fetching the value. c.mu must be held. The fetch is
+// shared by many callers, so it is not bound to the context of any of them;
+// it is bounded by the timeout of the wrapped Provider.
+func (c *CachingProvider) startLocked() *call {
+	cl := &call{done: make(chan struct{})}
+	c.inflight = cl
+	generation := c.generation
+	go func() {
+		cl.tag, cl.err = c.Provider.Latest(context.Background())
+		c.mu.Lock()
+		// Else Invalidate was called meanwhile, and c.inflight, if not nil,
+		// is a newer fetch.
+		if c.generation == generation {
+			if cl.err == nil {
+				c.tag = cl.tag
+				c.fetchedAt = time.Now()
+				c.valid = true
+			}
+			c.inflight = nil
+		}
+		c.mu.Unlock()
+		close(cl.done)
+	}()
+	return cl
+}
@@ -0,0 +1,134 @@
+package release_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marco-m/taschino/pkg/release"
+)
+
+// gatedProvider is a Provider whose calls wait for their answer: each of
+// them sends on started the channel of its tag.
+type gatedProvider struct {
+	started chan chan string
+	mu      sync.Mutex
+	calls   int
+}
+
+func newGatedProvider() *gatedProvider {
+	return &gatedProvider{started: make(chan chan string, 10)}
+}
+
+func (p *gatedProvider) Latest(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+	tag := make(chan string)
+	p.started <- tag
+	return <-tag, nil
+}
+
+func (p *gatedProvider) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+// waitStarted waits for a call of p to start, failing t if none does, and
+// returns the channel of its answer.
+func waitStarted(t *testing.T, p *gatedProvider,
+	failure string) chan<- string {
+	t.Helper()
+	select {
+	case tag := <-p.started:
+		return tag
+	case <-time.After(5 * time.Second):
+		t.Fatal(failure)
+		return nil
+	}
+}
+
+// latestAsync calls c.Latest in a new goroutine and returns the channel of
+// its tag.
+func latestAsync(t *testing.T, c *release.CachingProvider) <-chan string {
+	ch := make(chan string, 1)
+	go func() {
+		tag, err := c.Latest(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		ch <- tag
+	}()
+	return ch
+}
+
+func TestCachingProviderMemoizes(t *testing.T) {
+	p := newGatedProvider()
+	c := release.NewCachingProvider(p, time.Hour)
+
+	first := latestAsync(t, c)
+	waitStarted(t, p, "no fetch") <- "v1.0.0"
+	<-first
+	tag, err := c.Latest(context.Background())
+
+	if err != nil || tag != "v1.0.0" {
+		t.Fatalf("got tag %q, error %v; want v1.0.0", tag, err)
+	}
+	if n := p.Calls(); n != 1 {
+		t.Errorf("got %d calls, want 1", n)
+	}
+}
+
+func TestCachingProviderInvalidateDuringFetch(t *testing.T) {
+	p := newGatedProvider()
+	c := release.NewCachingProvider(p, time.Hour)
+	old := latestAsync(t, c)
+	oldTag := waitStarted(t, p, "no fetch")
+
+	// A new release is published while the fetch is in progress.
+	c.Invalidate()
+	oldTag <- "v1.0.0"
+	if tag := <-old; tag != "v1.0.0" {
+		t.Errorf("waiter of the old fetch: got %q, want v1.0.0", tag)
+	}
+	fresh := latestAsync(t, c)
+	waitStarted(t, p, "the value of the fetch started before Invalidate "+
+		"was kept") <- "v1.1.0"
+
+	if tag := <-fresh; tag != "v1.1.0" {
+		t.Errorf("got %q, want v1.1.0", tag)
+	}
+	tag, err := c.Latest(context.Background())
+	if err != nil || tag != "v1.1.0" {
+		t.Errorf("memoized: got tag %q, error %v; want v1.1.0", tag, err)
+	}
+	if n := p.Calls(); n != 2 {
+		t.Errorf("got %d calls, want 2", n)
+	}
+}
+
+func TestCachingProviderInvalidateDoesNotJoinOldFetch(t *testing.T) {
+	p := newGatedProvider()
+	c := release.NewCachingProvider(p, time.Hour)
+	old := latestAsync(t, c)
+	oldTag := waitStarted(t, p, "no fetch")
+
+	c.Invalidate()
+	fresh := latestAsync(t, c)
+	freshTag := waitStarted(t, p, "the call after Invalidate joined the "+
+		"fetch started before")
+	// The fetches end in the opposite order.
+	freshTag <- "v1.1.0"
+	if tag := <-fresh; tag != "v1.1.0" {
+		t.Errorf("got %q, want v1.1.0", tag)
+	}
+	oldTag <- "v1.0.0"
+	<-old
+
+	tag, err := c.Latest(context.Background())
+	if err != nil || tag != "v1.1.0" {
+		t.Errorf("memoized: got tag %q, error %v; want v1.1.0", tag, err)
+	}
+}
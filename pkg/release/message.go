@@ -0,0 +1,24 @@
+package release
+
+import "fmt"
+
+// FormatUpdateMessage returns a user-facing message announcing that version
+// latest is available, such as
+//
+//	A new version v1.3.0 is available (you have v1.2.3). See <releaseURL>.
+//
+// The last sentence is omitted if releaseURL is empty. It returns the empty
+// string if latest is not newer than current, or if either is not a valid
+// semver, so that the result can always be printed.
+func FormatUpdateMessage(current string, latest string, releaseURL string) string {
+	newer, err := IsNewer(current, latest)
+	if err != nil || !newer {
+		return ""
+	}
+	msg := fmt.Sprintf("A new version %s is available (you have %s).",
+		latest, current)
+	if releaseURL != "" {
+		msg += fmt.Sprintf(" See %s.", releaseURL)
+	}
+	return msg
+}
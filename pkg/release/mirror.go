@@ -0,0 +1,80 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MirrorsError is returned when all the base URLs of WithMirrors failed.
+// It wraps the error of the last mirror.
+type MirrorsError struct {
+	// Mirrors are the base URLs tried, in order.
+	Mirrors []string
+	// Errors are the corresponding errors.
+	Errors []error
+}
+
+func (e *MirrorsError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = fmt.Sprintf("%s: %s", e.Mirrors[i], err)
+	}
+	return "all mirrors failed: " + strings.Join(msgs, "; ")
+}
+
+func (e *MirrorsError) Unwrap() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e.Errors[len(e.Errors)-1]
+}
+
+// mirrored calls fn with the base URL of the GitHub API or, with WithMirrors,
+// with each mirror in turn, until fn succeeds or fails with an error that is
+// not worth trying the next mirror for. fn must make its requests with cfg,
+// the configuration for that base URL.
+func (gh *GitHub) mirrored(ctx context.Context,
+	fn func(base string, cfg *config) error) error {
+	if len(gh.cfg.mirrors) == 0 {
+		return fn(gh.cfg.apiURL(gitHubAPI), gh.cfg)
+	}
+	mirrorsErr := &MirrorsError{}
+	for _, base := range gh.cfg.mirrors {
+		base = strings.TrimRight(base, "/")
+		err := fn(base, gh.mirrorConfig(base))
+		if err == nil || !failover(err) || ctx.Err() != nil {
+			return err
+		}
+		gh.cfg.debug("mirror failed", "mirror", base, "error", err)
+		mirrorsErr.Mirrors = append(mirrorsErr.Mirrors, base)
+		mirrorsErr.Errors = append(mirrorsErr.Errors, err)
+	}
+	return mirrorsErr
+}
+
+// mirrorConfig returns the configuration for the requests to the mirror base.
+// A token from the environment is meant for the API it was chosen for, not for
+// a mirror that might be a third-party service: it is sent to the mirror only
+// if it is that API. A token of WithToken is sent to all mirrors.
+func (gh *GitHub) mirrorConfig(base string) *config {
+	if gh.cfg.tokenSet || gh.cfg.token == "" ||
+		base == strings.TrimRight(gh.cfg.apiURL(gitHubAPI), "/") {
+		return gh.cfg
+	}
+	cfg := *gh.cfg
+	cfg.token = ""
+	return &cfg
+}
+
+// failover reports whether err, from a mirror, is worth trying the next
+// mirror: a connection error or a server error (5xx). Any other error, such
+// as 404 Not Found, is the definitive answer.
+func failover(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	return isTransient(err)
+}
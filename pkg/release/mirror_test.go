@@ -0,0 +1,87 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// setenv sets the environment variable name to value for the duration of t.
+func setenv(t *testing.T, name string, value string) {
+	t.Helper()
+	prev, ok := os.LookupEnv(name)
+	if err := os.Setenv(name, value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(name, prev)
+		} else {
+			os.Unsetenv(name)
+		}
+	})
+}
+
+// authServer returns a server answering a latest release and recording the
+// Authorization header it received into got.
+func authServer(t *testing.T, got *string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			*got = r.Header.Get("Authorization")
+			fmt.Fprint(w, `{"tag_name":"v1.2.3"}`)
+		}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestMirrorsDoNotGetEnvToken(t *testing.T) {
+	setenv(t, "GITHUB_TOKEN", "env-secret")
+	var auth string
+	mirror := authServer(t, &auth)
+
+	_, err := NewGitHub("o", "r", WithMirrors([]string{mirror.URL})).
+		LatestRelease(context.Background())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth != "" {
+		t.Errorf("mirror got Authorization %q, want none", auth)
+	}
+}
+
+func TestMirrorsGetExplicitToken(t *testing.T) {
+	setenv(t, "GITHUB_TOKEN", "env-secret")
+	var auth string
+	mirror := authServer(t, &auth)
+
+	_, err := NewGitHub("o", "r", WithMirrors([]string{mirror.URL}),
+		WithToken("explicit")).LatestRelease(context.Background())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Bearer explicit"; auth != want {
+		t.Errorf("mirror got Authorization %q, want %q", auth, want)
+	}
+}
+
+func TestMirrorOfBaseURLGetsEnvToken(t *testing.T) {
+	setenv(t, "GH_ENTERPRISE_TOKEN", "enterprise-secret")
+	var auth string
+	api := authServer(t, &auth)
+
+	_, err := NewGitHub("o", "r", WithBaseURL(api.URL),
+		WithMirrors([]string{api.URL + "/"})).LatestRelease(context.Background())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Bearer enterprise-secret"; auth != want {
+		t.Errorf("API got Authorization %q, want %q", auth, want)
+	}
+}
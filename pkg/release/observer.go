@@ -0,0 +1,102 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Event describes a lookup of the latest release, as passed to the observer
+// of WithObserver.
+type Event struct {
+	// Owner and Repo identify the repository. For GitLab, Owner is empty and
+	// Repo is the project ID.
+	Owner string
+	Repo  string
+	// Duration is how long the lookup took, retries included.
+	Duration time.Duration
+	// Outcome summarizes the result of the lookup.
+	Outcome Outcome
+	// StatusCode is the HTTP status of the last response, or 0 if no response
+	// was received (for example on a cache hit or a network error).
+	StatusCode int
+	// CacheHit reports whether the result came from the cache without
+	// querying the API.
+	CacheHit bool
+	// Err is the error of the lookup, if any.
+	Err error
+}
+
+// Outcome is the result of a lookup, suitable as a metric label.
+type Outcome string
+
+const (
+	OutcomeSuccess      Outcome = "success"
+	OutcomeNotFound     Outcome = "not_found"
+	OutcomeRateLimited  Outcome = "rate_limited"
+	OutcomeUnauthorized Outcome = "unauthorized"
+	OutcomeUnavailable  Outcome = "unavailable"
+	OutcomeError        Outcome = "error"
+)
+
+// outcome returns the Outcome corresponding to err.
+func outcome(err error) Outcome {
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.Is(err, ErrNoRelease):
+		return OutcomeNotFound
+	case errors.Is(err, ErrRateLimited):
+		return OutcomeRateLimited
+	case errors.Is(err, ErrUnauthorized):
+		return OutcomeUnauthorized
+	case errors.Is(err, ErrUnavailable):
+		return OutcomeUnavailable
+	}
+	return OutcomeError
+}
+
+// observation collects, during a lookup, what the Event needs to know and
+// only the lower layers see.
+type observation struct {
+	statusCode int
+	cacheHit   bool
+}
+
+type observationKey struct{}
+
+// observe runs the lookup fn for owner/repo and, if there is an observer,
+// passes it the corresponding Event.
+func (cfg *config) observe(ctx context.Context, owner string, repo string,
+	fn func(ctx context.Context) (string, error)) (string, error) {
+	if cfg.observer == nil {
+		return fn(ctx)
+	}
+	obs := &observation{}
+	start := time.Now()
+	tag, err := fn(context.WithValue(ctx, observationKey{}, obs))
+	cfg.observer(Event{
+		Owner:      owner,
+		Repo:       repo,
+		Duration:   time.Since(start),
+		Outcome:    outcome(err),
+		StatusCode: obs.statusCode,
+		CacheHit:   obs.cacheHit,
+		Err:        err,
+	})
+	return tag, err
+}
+
+// markCacheHit records in the observation of the lookup in progress, if any,
+// that the result comes from the cache.
+func markCacheHit(ctx context.Context) {
+	if obs := observed(ctx); obs != nil {
+		obs.cacheHit = true
+	}
+}
+
+// observed returns the observation of the lookup in progress, or nil.
+func observed(ctx context.Context) *observation {
+	obs, _ := ctx.Value(observationKey{}).(*observation)
+	return obs
+}
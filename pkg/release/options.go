@@ -0,0 +1,573 @@
+package release
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures how the functions of this package query a release API.
+type Option func(*config)
+
+type config struct {
+	client    *http.Client
+	transport http.RoundTripper
+	tlsConfig *tls.Config
+	rootCAs   *x509.CertPool
+	timeout   time.Duration
+	token     string
+	// tokenSet is true if token comes from WithToken, even if empty.
+	tokenSet   bool
+	noEnvToken bool
+	userAgent  string
+	baseURL    string
+	perPage    int
+	allPages   bool
+	maxItems   int
+	// Retry on rate limit responses carrying a Retry-After header.
+	retryAfterAttempts int
+	retryAfterMaxWait  time.Duration
+	// Retry on transient errors, with exponential backoff.
+	retryAttempts  int
+	retryBaseDelay time.Duration
+	cache          *diskCache
+	offline        bool
+	logger         Logger
+	observer       func(Event)
+	prereleases    bool
+	comparator     Comparator
+	onUpdate       func(ctx context.Context, update Update) error
+	webhookURL     string
+	mirrors        []string
+	apiVersion     string
+	singleflight   bool
+	strictJSON     bool
+	tagPrefix      string
+	dryRun         bool
+	dryRunReport   *DryRunReport
+	noReleaseOK    bool
+	maxResponse    int64
+	tagMessages    bool
+	concurrency    int
+	// For self-update.
+	currentVersion string
+	checksumsAsset string
+	// assetExclusions, if not nil, replaces DefaultAssetExclusions.
+	assetExclusions *AssetExclusions
+	verifier        Verifier
+	requireSigned   bool
+	// For extraction.
+	stripTopDir bool
+}
+
+// defaultTimeout is the timeout of a request when WithTimeout is not used.
+const defaultTimeout = 5 * time.Second
+
+// defaultClient is the client used when WithHTTPClient is not used. It is
+// shared, so that connections are reused across calls. Its transport is a copy
+// of http.DefaultTransport with, explicitly, the proxy configuration of the
+// environment variables HTTPS_PROXY, HTTP_PROXY and NO_PROXY (see
+// http.ProxyFromEnvironment).
+var defaultClient = &http.Client{Transport: defaultTransport()}
+
+func defaultTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	return transport
+}
+
+// tagVersion returns the version of tag, without the prefix of WithTagPrefix.
+func (cfg *config) tagVersion(tag string) string {
+	return StripPrefix(tag, cfg.tagPrefix)
+}
+
+// applyTLSConfig sets the transport to a copy of the current one using the TLS
+// configuration of WithTLSConfig and WithRootCAs. With WithRootCAs only, the
+// TLS configuration of the current transport is kept, except for RootCAs.
+func (cfg *config) applyTLSConfig() {
+	base := cfg.transport
+	if base == nil {
+		base = cfg.client.Transport
+	}
+	var transport *http.Transport
+	switch t := base.(type) {
+	case nil:
+		transport = defaultTransport()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		cfg.warn("TLS configuration not applied to transport",
+			"type", fmt.Sprintf("%T", base))
+		return
+	}
+	var tlsConfig *tls.Config
+	switch {
+	case cfg.tlsConfig != nil:
+		tlsConfig = cfg.tlsConfig.Clone()
+	case transport.TLSClientConfig != nil:
+		tlsConfig = transport.TLSClientConfig.Clone()
+	default:
+		tlsConfig = &tls.Config{}
+	}
+	if cfg.rootCAs != nil {
+		tlsConfig.RootCAs = cfg.rootCAs
+	}
+	transport.TLSClientConfig = tlsConfig
+	cfg.transport = transport
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		client:      defaultClient,
+		timeout:     defaultTimeout,
+		userAgent:   defaultUserAgent(),
+		concurrency: defaultConcurrency,
+		comparator:  SemVer{},
+		apiVersion:  defaultAPIVersion,
+		maxResponse: defaultMaxResponseSize,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.tlsConfig != nil || cfg.rootCAs != nil {
+		cfg.applyTLSConfig()
+	}
+	transport := cfg.transport
+	if transport == nil {
+		transport = cfg.client.Transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	// Compressed responses whatever the transport.
+	client := *cfg.client
+	client.Transport = &gzipTransport{base: transport}
+	cfg.client = &client
+	if cfg.webhookURL != "" {
+		// After the client options, whatever their order.
+		cfg.onUpdate = webhook(cfg, cfg.webhookURL)
+	}
+	return cfg
+}
+
+// apiURL returns the API base URL to use, defaulting to def, without trailing
+// slashes.
+func (cfg *config) apiURL(def string) string {
+	if cfg.baseURL == "" {
+		return def
+	}
+	return strings.TrimRight(cfg.baseURL, "/")
+}
+
+// withTimeout returns a copy of ctx bounded by the configured timeout, if any.
+func (cfg *config) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if cfg.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, cfg.timeout)
+}
+
+// WithHTTPClient uses client instead of a default http.Client, for example to
+// share a transport or to talk to an httptest.Server. The request timeout of
+// this package still applies on top of any timeout configured in client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(cfg *config) {
+		if client != nil {
+			cfg.client = client
+		}
+	}
+}
+
+// WithTransport makes the requests with transport, for full control over
+// connections and proxying. It applies also to the client of WithHTTPClient,
+// if any. Note that a transport built from scratch does not use a proxy unless
+// its Proxy field is set, for example to http.ProxyFromEnvironment.
+//
+// Whatever the transport, responses are requested gzip-compressed and
+// decompressed transparently.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(cfg *config) {
+		cfg.transport = transport
+	}
+}
+
+// WithTLSConfig makes the requests use the TLS configuration tlsConfig, for
+// example to trust the private CA of a GitHub Enterprise instance. It applies
+// to the transport of WithTransport or WithHTTPClient, if any, or else to the
+// default one, keeping their other settings, such as the proxy; it has no
+// effect if that transport is not an *http.Transport. Certificate
+// verification is skipped only if tlsConfig explicitly says so.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(cfg *config) {
+		cfg.tlsConfig = tlsConfig.Clone()
+	}
+}
+
+// WithRootCAs is like WithTLSConfig, but changes only the set of root
+// certificate authorities, such as a private CA bundle, that the requests
+// trust. It combines with WithTLSConfig, whatever their order.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(cfg *config) {
+		cfg.rootCAs = pool
+	}
+}
+
+// WithTimeout sets the timeout of a request to d (default 5 seconds). A zero or
+// negative d means no timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(cfg *config) {
+		cfg.timeout = d
+	}
+}
+
+// WithToken authenticates the request with token, for example a GitHub
+// personal access token. This raises the API rate limit and gives access to
+// private repositories. An empty token means an anonymous request. For GitHub,
+// it takes precedence over the token from the environment (see NewGitHub).
+func WithToken(token string) Option {
+	return func(cfg *config) {
+		cfg.token = token
+		cfg.tokenSet = true
+	}
+}
+
+// WithoutEnvToken disables reading the GitHub token from the environment (see
+// NewGitHub), for callers wanting strict control over authentication.
+func WithoutEnvToken() Option {
+	return func(cfg *config) {
+		cfg.noEnvToken = true
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the request (default
+// "taschino/<version>").
+func WithUserAgent(userAgent string) Option {
+	return func(cfg *config) {
+		cfg.userAgent = userAgent
+	}
+}
+
+// WithBaseURL sets the base URL of the API, for example
+// "https://github.mycorp.com/api/v3" for a GitHub Enterprise instance. The
+// default is the public API of the provider, for GitHub
+// "https://api.github.com".
+func WithBaseURL(baseURL string) Option {
+	return func(cfg *config) {
+		cfg.baseURL = baseURL
+	}
+}
+
+// WithPerPage sets the number of items per page of a paginated request, such
+// as ListReleases. GitHub defaults to 30 and accepts at most 100.
+func WithPerPage(n int) Option {
+	return func(cfg *config) {
+		cfg.perPage = n
+	}
+}
+
+// WithAllPages makes a paginated request, such as ListReleases, follow the
+// Link header of the response to fetch all the pages, instead of only the
+// first one.
+func WithAllPages() Option {
+	return func(cfg *config) {
+		cfg.allPages = true
+	}
+}
+
+// WithMaxReleases limits the releases listed, by ListReleases and by the
+// lookups based on it such as LatestStable, to the n most recent ones,
+// fetching as many pages as needed to collect them (the default is the first
+// page only). Per page, it requests n releases, up to the maximum of 100,
+// unless WithPerPage is used.
+//
+// The limit applies before any filtering: drafts and prereleases count
+// towards it, so that, for example, LatestStable with WithMaxReleases(10)
+// returns the highest stable release among the 10 most recent releases, and
+// fails with ErrNoStableRelease if they are all prereleases.
+func WithMaxReleases(n int) Option {
+	return func(cfg *config) {
+		cfg.maxItems = n
+	}
+}
+
+// WithRetryAfter retries a request refused by a secondary rate limit, waiting
+// the duration requested by the Retry-After header of the response, for a
+// total of at most maxAttempts attempts, or one minute if the header is missing
+// but the message of the response tells a secondary rate limit. It does not
+// retry if the requested wait is longer than maxWait. Canceling the context of
+// the request stops waiting. The default is not to retry.
+func WithRetryAfter(maxAttempts int, maxWait time.Duration) Option {
+	return func(cfg *config) {
+		cfg.retryAfterAttempts = maxAttempts
+		cfg.retryAfterMaxWait = maxWait
+	}
+}
+
+// WithRetry retries a request failing with a transient error (a connection
+// error or status 502, 503, 504), for a total of at most attempts attempts. It
+// waits with exponential backoff and jitter: about baseDelay before the second
+// attempt, twice as much before the third, and so on. It never retries on
+// other errors, such as ErrNoRelease or ErrUnauthorized. Canceling the context
+// of the request stops waiting. The default is not to retry.
+func WithRetry(attempts int, baseDelay time.Duration) Option {
+	return func(cfg *config) {
+		cfg.retryAttempts = attempts
+		cfg.retryBaseDelay = baseDelay
+	}
+}
+
+// WithCache caches on disk, in directory dir, the result of the lookup of the
+// latest release (for example GitHubLatest) for ttl. Within ttl, the lookup
+// does not make any request. After ttl, it makes a conditional request, which
+// does not count against the rate limit if the latest release did not change.
+// A corrupt cache file is ignored.
+func WithCache(dir string, ttl time.Duration) Option {
+	return func(cfg *config) {
+		cfg.cache = &diskCache{dir: dir, ttl: ttl}
+	}
+}
+
+// WithOffline makes the lookup of the latest release (for example
+// GitHubLatest) return the value in the cache of WithCache, however old,
+// without making any request. If the cache has no value, the error wraps
+// ErrNoCachedValue. See also CachedLatest.
+func WithOffline() Option {
+	return func(cfg *config) {
+		cfg.offline = true
+	}
+}
+
+// WithLogger makes the functions of this package log to logger, typically a
+// *slog.Logger, at debug level: the URL of each request, the status and the
+// rate-limit headers of each response and the retry decisions. The default is
+// not to log.
+func WithLogger(logger Logger) Option {
+	return func(cfg *config) {
+		cfg.logger = logger
+	}
+}
+
+// WithObserver calls observer at the end of each lookup of the latest release,
+// successful or not, for example to record metrics. observer is called
+// synchronously, so it should return quickly.
+func WithObserver(observer func(Event)) Option {
+	return func(cfg *config) {
+		cfg.observer = observer
+	}
+}
+
+// WithPrereleases makes LatestWithinMajor, LatestInstallable and
+// LatestMatchingPattern consider also prereleases.
+func WithPrereleases() Option {
+	return func(cfg *config) {
+		cfg.prereleases = true
+	}
+}
+
+// WithComparator makes IsNewer, Newest, NewestNewerThan, Watch and
+// SelfUpdateFromURL compare versions with c instead of semver, for example
+// with CalVer. The lookups listing releases, such as LatestStable, still
+// select releases by semver.
+func WithComparator(c Comparator) Option {
+	return func(cfg *config) {
+		if c != nil {
+			cfg.comparator = c
+		}
+	}
+}
+
+// WithOnUpdate makes Watch call onUpdate for each newer version found. An error
+// of onUpdate is reported on the channel of Watch and does not stop it.
+func WithOnUpdate(onUpdate func(ctx context.Context, update Update) error) Option {
+	return func(cfg *config) {
+		cfg.onUpdate = onUpdate
+		cfg.webhookURL = ""
+	}
+}
+
+// WithWebhook makes Watch POST each newer version found to url, as the JSON
+// object {"current": "v1.2.3", "latest": "v1.3.0"}, with the HTTP client and
+// timeout of the other options. A status other than 2xx is an error of the
+// callback, as for WithOnUpdate. It replaces the callback of WithOnUpdate.
+func WithWebhook(url string) Option {
+	return func(cfg *config) {
+		cfg.webhookURL = url
+	}
+}
+
+// WithMirrors makes the lookups of the latest GitHub release, such as
+// GitHubLatest, try the API base URLs baseURLs in order, instead of the one of
+// WithBaseURL, until one succeeds. A connection error or a server error (5xx)
+// moves on to the next mirror; any other answer, such as ErrNoRelease, is
+// definitive. If all mirrors fail, the error is a *MirrorsError.
+//
+// Only the token of WithToken is sent to the mirrors. A token from the
+// environment, such as GITHUB_TOKEN, is sent only to the API it is meant for,
+// api.github.com or the one of WithBaseURL, if among baseURLs.
+func WithMirrors(baseURLs []string) Option {
+	return func(cfg *config) {
+		cfg.mirrors = append([]string(nil), baseURLs...)
+	}
+}
+
+// WithAPIVersion sets the version of the GitHub REST API to request, with the
+// header X-GitHub-Api-Version, such as "2022-11-28". The default is a version
+// known to work with this package; the empty string sends no header, that is,
+// requests the latest version.
+func WithAPIVersion(version string) Option {
+	return func(cfg *config) {
+		cfg.apiVersion = version
+	}
+}
+
+// WithSingleflight makes concurrent lookups of the latest release of the same
+// GitHub repository, from any goroutine, share a single request and its
+// result, error included. Canceling the context of a lookup stops waiting for
+// the shared request, but not the request itself. To also reuse the result
+// after the request is done, see CachingProvider.
+func WithSingleflight() Option {
+	return func(cfg *config) {
+		cfg.singleflight = true
+	}
+}
+
+// WithStrictJSON makes the decoding of the API responses strict: a field
+// unknown to the types of this package, or anything after the JSON value,
+// such as an HTML page appended by a proxy, is an error. By default, decoding
+// is lenient and ignores unknown fields.
+//
+// The types of this package, such as Release, declare only the fields they
+// use, while the real API returns many more: strict decoding is meant for
+// tests against recorded responses reduced to those fields, to detect when
+// the shape of the API changes.
+func WithStrictJSON() Option {
+	return func(cfg *config) {
+		cfg.strictJSON = true
+	}
+}
+
+// WithTagPrefix makes the functions of this package treat the tags of the
+// releases as versions with prefix, such as "release-" for tags like
+// "release-1.2.3": the prefix is removed, as StripPrefix, before the tag is
+// validated and compared as a semver, by the lookups listing releases or tags
+// (such as LatestStable, LatestMatching and GitHubLatestTag), ChangelogBetween,
+// CheckForUpdate, Watch and SelfUpdate. The returned tags and releases keep
+// the prefix. Tags without the prefix are taken as they are. After removing
+// the prefix, the leading "v" is still optional.
+func WithTagPrefix(prefix string) Option {
+	return func(cfg *config) {
+		cfg.tagPrefix = prefix
+	}
+}
+
+// WithDryRun makes SelfUpdate and SelfUpdateFromURL perform all the steps of
+// the update, download and verification included, except the replacement of
+// the executable, and describe in report, if not nil, the update that would
+// have been applied. See SelfUpdate.
+func WithDryRun(report *DryRunReport) Option {
+	return func(cfg *config) {
+		cfg.dryRun = true
+		cfg.dryRunReport = report
+	}
+}
+
+// WithNoReleaseAsUpToDate makes CheckForUpdate report a repository without
+// releases as up to date, instead of failing with ErrNoRelease. See
+// CheckForUpdate.
+func WithNoReleaseAsUpToDate() Option {
+	return func(cfg *config) {
+		cfg.noReleaseOK = true
+	}
+}
+
+// defaultMaxResponseSize is the maximum size of a JSON response when
+// WithMaxResponseSize is not used.
+const defaultMaxResponseSize = 10 << 20
+
+// WithMaxResponseSize sets the maximum size, in bytes, of the body of a JSON
+// response of the API, by default 10 MiB, against a misbehaving proxy or
+// endpoint. A bigger response fails with ErrResponseTooLarge. If n is 0 or
+// negative, there is no limit. It does not apply to the download of assets.
+func WithMaxResponseSize(n int64) Option {
+	return func(cfg *config) {
+		cfg.maxResponse = n
+	}
+}
+
+// WithTagMessageFallback makes ChangelogBetween use the message of the
+// annotated git tag of a release without description, for the projects that
+// write their notes in the tags. It costs up to two requests per release
+// without description. See ChangelogBetween.
+func WithTagMessageFallback() Option {
+	return func(cfg *config) {
+		cfg.tagMessages = true
+	}
+}
+
+// WithConcurrency sets the maximum number of concurrent requests of the
+// functions making many requests, such as LatestMany (default 4).
+func WithConcurrency(n int) Option {
+	return func(cfg *config) {
+		cfg.concurrency = n
+	}
+}
+
+// WithCurrentVersion sets the version of the running program, for SelfUpdate.
+// The default is the version in the build information of the program, which
+// is available only when built with "go install module@version".
+func WithCurrentVersion(v string) Option {
+	return func(cfg *config) {
+		cfg.currentVersion = v
+	}
+}
+
+// WithAssetExclusions makes SelfUpdate, LatestInstallable and DownloadAndVerify
+// select the asset for the platform as SelectAssetExcluding with exclusions,
+// instead of as SelectAsset. See DefaultAssetExclusions.
+func WithAssetExclusions(exclusions AssetExclusions) Option {
+	return func(cfg *config) {
+		cfg.assetExclusions = &exclusions
+	}
+}
+
+// WithChecksumsAsset sets the name of the checksums asset used by SelfUpdate
+// to verify the downloaded binary. By default, SelfUpdate looks for an asset
+// called "checksums.txt", "SHA256SUMS" or ending with "_checksums.txt" (as
+// made by GoReleaser).
+func WithChecksumsAsset(name string) Option {
+	return func(cfg *config) {
+		cfg.checksumsAsset = name
+	}
+}
+
+// WithVerifier makes SelfUpdate verify the downloaded binary with v, for
+// example Ed25519Verifier or CosignVerifier, against the signature asset of
+// the release called as the binary asset plus ".sig" or ".asc". SelfUpdate
+// then refuses to install a binary without a valid signature.
+func WithVerifier(v Verifier) Option {
+	return func(cfg *config) {
+		cfg.verifier = v
+	}
+}
+
+// WithRequireSignature makes SelfUpdate fail if no Verifier is configured with
+// WithVerifier, as a safeguard against installing unsigned binaries because of
+// a configuration mistake.
+func WithRequireSignature() Option {
+	return func(cfg *config) {
+		cfg.requireSigned = true
+	}
+}
+
+// WithStripTopDir makes the extraction functions, such as ExtractTarGz, strip
+// the leading directory component of each entry of the archive, for archives
+// containing a single top-level directory.
+func WithStripTopDir() Option {
+	return func(cfg *config) {
+		cfg.stripTopDir = true
+	}
+}
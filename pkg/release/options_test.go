@@ -0,0 +1,151 @@
+package release
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+// clientTLSConfig returns the TLS configuration of the transport of cfg.
+func clientTLSConfig(t *testing.T, cfg *config) *tls.Config {
+	t.Helper()
+	gz, ok := cfg.client.Transport.(*gzipTransport)
+	if !ok {
+		t.Fatalf("transport: got %T, want *gzipTransport", cfg.client.Transport)
+	}
+	transport, ok := gz.base.(*http.Transport)
+	if !ok {
+		t.Fatalf("base transport: got %T, want *http.Transport", gz.base)
+	}
+	if transport.TLSClientConfig == nil {
+		t.Fatal("no TLS configuration")
+	}
+	return transport.TLSClientConfig
+}
+
+func TestWithRootCAsKeepsTransportTLSConfig(t *testing.T) {
+	original := &tls.Config{MinVersion: tls.VersionTLS13, ServerName: "ghe.example"}
+	pool := x509.NewCertPool()
+	for _, opt := range []struct {
+		name   string
+		option Option
+	}{
+		{"WithTransport", WithTransport(&http.Transport{TLSClientConfig: original})},
+		{"WithHTTPClient", WithHTTPClient(&http.Client{
+			Transport: &http.Transport{TLSClientConfig: original},
+		})},
+	} {
+		t.Run(opt.name, func(t *testing.T) {
+			got := clientTLSConfig(t, newConfig([]Option{opt.option,
+				WithRootCAs(pool)}))
+
+			if got.RootCAs != pool {
+				t.Error("RootCAs not set")
+			}
+			if got.MinVersion != tls.VersionTLS13 {
+				t.Errorf("MinVersion: got %x, want %x", got.MinVersion,
+					tls.VersionTLS13)
+			}
+			if got.ServerName != "ghe.example" {
+				t.Errorf("ServerName: got %q, want %q", got.ServerName,
+					"ghe.example")
+			}
+			if original.RootCAs != nil {
+				t.Error("the TLS configuration of the caller was modified")
+			}
+		})
+	}
+}
+
+func TestWithTLSConfigAndRootCAsInAnyOrder(t *testing.T) {
+	pool := x509.NewCertPool()
+	tlsConfig := &tls.Config{ServerName: "ghe.example"}
+	for _, opts := range [][]Option{
+		{WithTLSConfig(tlsConfig), WithRootCAs(pool)},
+		{WithRootCAs(pool), WithTLSConfig(tlsConfig)},
+	} {
+		got := clientTLSConfig(t, newConfig(opts))
+
+		if got.RootCAs != pool || got.ServerName != "ghe.example" {
+			t.Errorf("got RootCAs %p ServerName %q, want %p %q", got.RootCAs,
+				got.ServerName, pool, "ghe.example")
+		}
+	}
+}
+
+// proxyServer returns a server acting as an HTTP proxy, answering a latest
+// release to any request, and recording the URL requested through it.
+func proxyServer(t *testing.T, got *string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Through a proxy, the request line has the absolute URL.
+			*got = r.URL.String()
+			fmt.Fprint(w, `{"tag_name":"v1.2.3"}`)
+		}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// proxiedAPI is the base URL of an API reachable only through the proxy (not
+// a loopback address, which ProxyFromEnvironment never proxies).
+const proxiedAPI = "http://api.example.test"
+
+func TestWithTransportProxy(t *testing.T) {
+	var got string
+	proxy := proxyServer(t, &got)
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tag, err := NewGitHub("o", "r", WithBaseURL(proxiedAPI), WithTransport(
+		&http.Transport{Proxy: http.ProxyURL(proxyURL)})).
+		Latest(context.Background())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != "v1.2.3" {
+		t.Errorf("tag: got %q, want %q", tag, "v1.2.3")
+	}
+	if want := proxiedAPI + "/repos/o/r/releases/latest"; got != want {
+		t.Errorf("proxy saw %q, want %q", got, want)
+	}
+}
+
+func TestDefaultTransportUsesEnvironmentProxy(t *testing.T) {
+	// ProxyFromEnvironment reads the environment only once per process:
+	// check it in a new process.
+	if os.Getenv("TASCHINO_PROXY_TEST") == "1" {
+		tag, err := NewGitHub("o", "r", WithBaseURL(proxiedAPI)).
+			Latest(context.Background())
+		if err != nil || tag != "v1.2.3" {
+			t.Fatalf("got tag %q, error %v; want v1.2.3", tag, err)
+		}
+		return
+	}
+	var got string
+	proxy := proxyServer(t, &got)
+	cmd := exec.Command(os.Args[0],
+		"-test.run=^TestDefaultTransportUsesEnvironmentProxy$")
+	cmd.Env = append(os.Environ(), "TASCHINO_PROXY_TEST=1",
+		"HTTP_PROXY="+proxy.URL, "http_proxy="+proxy.URL, "NO_PROXY=",
+		"no_proxy=", "REQUEST_METHOD=")
+
+	out, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if want := proxiedAPI + "/repos/o/r/releases/latest"; got != want {
+		t.Errorf("proxy saw %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+package release
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MissingKeysError is returned by Outdated, together with the outdated
+// components, when the installed and available versions do not list the same
+// components.
+type MissingKeysError struct {
+	// NotAvailable are the installed components without an available version,
+	// sorted.
+	NotAvailable []string
+	// NotInstalled are the available components that are not installed,
+	// sorted.
+	NotInstalled []string
+}
+
+func (e *MissingKeysError) Error() string {
+	var msgs []string
+	if len(e.NotAvailable) > 0 {
+		msgs = append(msgs, "not available: "+strings.Join(e.NotAvailable, ", "))
+	}
+	if len(e.NotInstalled) > 0 {
+		msgs = append(msgs, "not installed: "+strings.Join(e.NotInstalled, ", "))
+	}
+	return "missing components: " + strings.Join(msgs, "; ")
+}
+
+// Outdated compares, for each component (key) present in both installed and
+// available, the installed version with the available one, as
+// CompareDetailed, and returns the Delta of the components for which an
+// upgrade is available.
+//
+// If a key is only in one of the maps, Outdated still compares the others, and
+// returns their result together with a *MissingKeysError listing the missing
+// keys. If a version is not a valid semver, it returns only an error, naming
+// the component.
+func Outdated(installed map[string]string,
+	available map[string]string) (map[string]Delta, error) {
+	missing := &MissingKeysError{}
+	for key := range available {
+		if _, ok := installed[key]; !ok {
+			missing.NotInstalled = append(missing.NotInstalled, key)
+		}
+	}
+	// Sorted, so that the error of an invalid version is deterministic.
+	keys := make([]string, 0, len(installed))
+	for key := range installed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	outdated := make(map[string]Delta)
+	for _, key := range keys {
+		cur := installed[key]
+		latest, ok := available[key]
+		if !ok {
+			missing.NotAvailable = append(missing.NotAvailable, key)
+			continue
+		}
+		delta, err := CompareDetailed(cur, latest)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", key, err)
+		}
+		if delta.Direction < 0 {
+			outdated[key] = delta
+		}
+	}
+	if len(missing.NotAvailable) > 0 || len(missing.NotInstalled) > 0 {
+		sort.Strings(missing.NotInstalled)
+		return outdated, missing
+	}
+	return outdated, nil
+}
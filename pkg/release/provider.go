@@ -0,0 +1,17 @@
+package release
+
+import "context"
+
+// Provider is a source of releases, such as a GitHub repository.
+type Provider interface {
+	// Latest returns the tag of the latest release as an opaque string (that
+	// is, it might be or not a valid semver string).
+	Latest(ctx context.Context) (string, error)
+}
+
+var (
+	_ Provider = (*GitHub)(nil)
+	_ Provider = (*GitLab)(nil)
+	_ Provider = (*Gitea)(nil)
+	_ Provider = (*FallbackProvider)(nil)
+)
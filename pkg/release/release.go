@@ -2,55 +2,190 @@ package release
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	"golang.org/x/mod/semver"
 )
 
+// gitHubAPI is the base URL of the public GitHub API.
+const gitHubAPI = "https://api.github.com"
+
 // GitHubLatest queries the GitHub releases API and returns the tag of the
 // latest release as an opaque string (that is, it might be or not a valid
 // semver string).
-func GitHubLatest(owner string, repo string) (string, error) {
-	// https://developer.github.com/v3/repos/releases/#get-the-latest-release
-	// API: GET /repos/:owner/:repo/releases/latest
-	api_url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest",
-		owner, repo)
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, api_url, nil)
-	if err != nil {
-		return "", fmt.Errorf("create http request: %w", err)
+func GitHubLatest(owner string, repo string, opts ...Option) (string, error) {
+	return GitHubLatestContext(context.Background(), owner, repo, opts...)
+}
+
+// GitHubLatestContext is like GitHubLatest, but the request is bound to ctx,
+// so that canceling ctx aborts an in-flight request. The request timeout
+// applies on top of any deadline of ctx.
+func GitHubLatestContext(ctx context.Context, owner string, repo string,
+	opts ...Option) (string, error) {
+	return NewGitHub(owner, repo, opts...).Latest(ctx)
+}
+
+// GitHub is the Provider of the releases of a GitHub repository.
+type GitHub struct {
+	owner string
+	repo  string
+	cfg   *config
+}
+
+// NewGitHub returns the Provider of the releases of the GitHub repository
+// owner/repo.
+//
+// The token is, by precedence: the one of WithToken; the environment variable
+// GITHUB_TOKEN or GH_TOKEN (GH_ENTERPRISE_TOKEN or GITHUB_ENTERPRISE_TOKEN
+// with WithBaseURL), unless WithoutEnvToken is used; none (anonymous).
+func NewGitHub(owner string, repo string, opts ...Option) *GitHub {
+	cfg := newConfig(opts)
+	if !cfg.tokenSet && !cfg.noEnvToken {
+		cfg.token = gitHubEnvToken(cfg.apiURL(gitHubAPI) != gitHubAPI)
 	}
-	client := &http.Client{}
+	return &GitHub{owner: owner, repo: repo, cfg: cfg}
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("http client Do: %w", err)
+// gitHubEnvToken returns the GitHub token from the environment, following the
+// conventions of the GitHub CLI.
+func gitHubEnvToken(enterprise bool) string {
+	names := []string{"GITHUB_TOKEN", "GH_TOKEN"}
+	if enterprise {
+		names = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode == http.StatusNotFound {
-		return "", fmt.Errorf("no release found at %s", api_url)
+	for _, name := range names {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
 	}
+	return ""
+}
 
-	type Response struct {
-		TagName string `json:"tag_name"`
+// Latest returns the tag of the latest release.
+func (gh *GitHub) Latest(ctx context.Context) (string, error) {
+	var res latestResult
+	err := gh.observeLatest(ctx, &res)
+	return res.tag, err
+}
+
+// latestResult is the result of a lookup of the latest release.
+type latestResult struct {
+	tag string
+	// checkedAt is when tag was fetched from the API, possibly by a previous
+	// lookup if fromCache.
+	checkedAt time.Time
+	fromCache bool
+	// observation is what the lookup recorded for the Event of WithObserver,
+	// when shared by WithSingleflight.
+	observation observation
+}
+
+// observeLatest looks up the latest release into res, as Latest.
+func (gh *GitHub) observeLatest(ctx context.Context, res *latestResult) error {
+	fetch := gh.latest
+	if gh.cfg.singleflight {
+		fetch = gh.sharedLatest
 	}
-	var response Response
+	_, err := gh.cfg.observe(ctx, gh.owner, gh.repo,
+		func(ctx context.Context) (string, error) {
+			var err error
+			*res, err = fetch(ctx)
+			return res.tag, err
+		})
+	return err
+}
 
-	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(&response); err != nil {
-		return "", fmt.Errorf("parsing JSON response: %w", err)
+// sharedLatest is latest, shared with the concurrent lookups of the same
+// repository, with the same API and token. Each of them gets the observation
+// of the shared lookup, for WithObserver.
+func (gh *GitHub) sharedLatest(ctx context.Context) (latestResult, error) {
+	key := gh.cacheKey().url + "\x00" + gh.cfg.token
+	res, err := latestFlights.do(ctx, key, func() (latestResult, error) {
+		obs := &observation{}
+		res, err := gh.latest(context.WithValue(detached{ctx},
+			observationKey{}, obs))
+		res.observation = *obs
+		return res, err
+	})
+	if obs := observed(ctx); obs != nil {
+		*obs = res.observation
 	}
+	return res, err
+}
 
-	if response.TagName == "" {
-		return "", fmt.Errorf("parsing JSON response: missing 'field tag_name'")
+func (gh *GitHub) latest(ctx context.Context) (latestResult, error) {
+	if gh.cfg.cache != nil || gh.cfg.offline {
+		entry, hit, err := gh.latestFromCache(ctx)
+		if err != nil {
+			return latestResult{}, err
+		}
+		return latestResult{tag: entry.Tag, checkedAt: entry.FetchedAt,
+			fromCache: hit}, nil
 	}
+	release, err := gh.LatestRelease(ctx)
+	if err != nil {
+		return latestResult{}, err
+	}
+	return latestResult{tag: release.TagName, checkedAt: time.Now()}, nil
+}
 
-	return response.TagName, nil
+// latestFromCache is Latest when using a disk cache or in offline mode. It
+// also reports whether the entry comes from the cache, without asking the API.
+func (gh *GitHub) latestFromCache(ctx context.Context) (CacheEntry, bool, error) {
+	cache := gh.cfg.cache
+	if cache == nil {
+		return CacheEntry{}, false, fmt.Errorf("%w: offline mode without a cache "+
+			"(use WithCache)", ErrNoCachedValue)
+	}
+	key := gh.cacheKey()
+	entry, ok := cache.load(key)
+	if gh.cfg.offline {
+		if !ok {
+			return CacheEntry{}, false, fmt.Errorf("%w for %s/%s", ErrNoCachedValue,
+				gh.owner, gh.repo)
+		}
+		markCacheHit(ctx)
+		return entry, true, nil
+	}
+	if ok && time.Since(entry.FetchedAt) < cache.ttl {
+		markCacheHit(ctx)
+		return entry, true, nil
+	}
+	entry, err := gh.LatestCached(ctx, entry)
+	if err != nil {
+		return CacheEntry{}, false, err
+	}
+	// A failure to write the cache is not a failure of the lookup.
+	if err := cache.store(key, entry); err != nil {
+		gh.cfg.warn("cannot write cache", "error", err)
+	}
+	return entry, false, nil
+}
+
+// defaultAPIVersion is the GitHub REST API version requested when
+// WithAPIVersion is not used.
+const defaultAPIVersion = "2022-11-28"
+
+// apiHeader returns the header to send with every request to the GitHub API.
+func (gh *GitHub) apiHeader() http.Header {
+	header := http.Header{}
+	// https://docs.github.com/en/rest/about-the-rest-api/api-versions
+	header.Set("Accept", "application/vnd.github+json")
+	if gh.cfg.apiVersion != "" {
+		header.Set("X-GitHub-Api-Version", gh.cfg.apiVersion)
+	}
+	return header
+}
+
+// cacheKey identifies the repository of gh among all the GitHub instances.
+func (gh *GitHub) cacheKey() cacheKey {
+	return cacheKey{
+		name: gh.owner + "_" + gh.repo,
+		url:  gh.cfg.apiURL(gitHubAPI) + "/repos/" + gh.owner + "/" + gh.repo,
+	}
 }
 
 // Compare returns:
@@ -58,14 +193,44 @@ func GitHubLatest(owner string, repo string) (string, error) {
 // -1 if curV < latestV;
 // +1 if curV > latestV;
 // error if curV or latestV are an invalid semver string.
+// The leading "v" is optional: "1.2.3" is the same as "v1.2.3".
 func Compare(curV string, latestV string) (int, error) {
-	if !semver.IsValid(curV) {
-		return 0,
-			fmt.Errorf("installed version is not a valid semver: %s", curV)
+	cur, err := validVersion(curV, "installed")
+	if err != nil {
+		return 0, err
+	}
+	latest, err := validVersion(latestV, "latest")
+	if err != nil {
+		return 0, err
+	}
+	return semver.Compare(cur, latest), nil
+}
+
+// TryCompare is like Compare, but a latestV that is not a valid semver, which
+// GitHubLatest can return, is not an error: it returns comparable false
+// instead. The intended fallback is then to compare the strings, and to tell
+// the user that a different release is tagged if curV != latestV, rather than
+// that an update is available. It returns an error only if curV is not a valid
+// semver, since that is a mistake of the caller.
+func TryCompare(curV string, latestV string) (result int, comparable bool,
+	err error) {
+	cur, err := validVersion(curV, "installed")
+	if err != nil {
+		return 0, false, err
+	}
+	latest := normalize(latestV)
+	if !semver.IsValid(latest) {
+		return 0, false, nil
 	}
-	if !semver.IsValid(latestV) {
-		return 0,
-			fmt.Errorf("latest version is not a valid semver: %s", latestV)
+	return semver.Compare(cur, latest), true, nil
+}
+
+// validVersion returns v normalized, or an error mentioning what version it
+// is if not a valid semver.
+func validVersion(v string, what string) (string, error) {
+	n := normalize(v)
+	if !semver.IsValid(n) {
+		return "", fmt.Errorf("%s version is not a valid semver: %s", what, v)
 	}
-	return semver.Compare(curV, latestV), nil
+	return n, nil
 }
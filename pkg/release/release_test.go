@@ -0,0 +1,68 @@
+package release_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marco-m/taschino/pkg/release"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name   string
+		cur    string
+		latest string
+		want   int
+	}{
+		{"older without v", "1.2.3", "v1.2.4", -1},
+		{"older with v", "v1.2.3", "v1.2.4", -1},
+		{"newer", "v1.3.0", "1.2.9", +1},
+		{"equal with v", "v1.2.3", "v1.2.3", 0},
+		{"equal with mixed prefixes", "1.2.3", "v1.2.3", 0},
+		{"equal with mixed prefixes, reversed", "v1.2.3", "1.2.3", 0},
+		{"prerelease is older", "v1.2.3-rc.1", "v1.2.3", -1},
+		{"build metadata is ignored", "v1.2.3+abc", "v1.2.3+def", 0},
+		{"major only", "v1", "v1.0.0", 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := release.Compare(tc.cur, tc.latest)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("Compare(%q, %q): got %d, want %d", tc.cur, tc.latest,
+					got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCompareInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		cur     string
+		latest  string
+		wantErr string
+	}{
+		{"empty current", "", "v1.2.3", "installed version"},
+		{"garbage current", "banana", "v1.2.3", "installed version"},
+		{"empty latest", "v1.2.3", "", "latest version"},
+		{"garbage latest", "v1.2.3", "nightly", "latest version"},
+		{"double v", "vv1.2.3", "v1.2.3", "installed version"},
+		{"leading zero", "v1.02.3", "v1.2.3", "installed version"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := release.Compare(tc.cur, tc.latest)
+
+			if err == nil {
+				t.Fatalf("Compare(%q, %q): got no error", tc.cur, tc.latest)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("got error %q, want it to mention %q", err, tc.wantErr)
+			}
+		})
+	}
+}
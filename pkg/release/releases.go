@@ -0,0 +1,203 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Release describes a release. Fields not provided by the API have their zero
+// value; for example, PublishedAt is the zero time for a draft.
+type Release struct {
+	// URL is the API URL of the release. See Repository.
+	URL         string    `json:"url"`
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	HTMLURL     string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+	Body        string    `json:"body"`
+	Prerelease  bool      `json:"prerelease"`
+	Draft       bool      `json:"draft"`
+	Assets      []Asset   `json:"assets"`
+	// BodySource tells where Body comes from. It is set only by
+	// ChangelogBetween with WithTagMessageFallback.
+	BodySource BodySource `json:"-"`
+}
+
+// BodySource is the origin of the Body of a Release.
+type BodySource string
+
+const (
+	// BodyFromRelease means that Body is the description of the release.
+	BodyFromRelease BodySource = "release"
+	// BodyFromTag means that the release has no description and Body is the
+	// message of its annotated git tag.
+	BodyFromTag BodySource = "tag"
+)
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type"`
+	// URL is the API URL of the asset, used to download it with a token.
+	URL                string `json:"url"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+	DownloadCount      int    `json:"download_count"`
+}
+
+// Asset returns the asset of r called name. If there is no such asset, the
+// error wraps ErrNoAsset.
+func (r *Release) Asset(name string) (*Asset, error) {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: release %s has no asset %s", ErrNoAsset,
+		r.TagName, name)
+}
+
+// OlderThan reports whether release was published more than d ago, for
+// example to adopt a release only after some bake time. A release that is not
+// published, such as a draft, whose PublishedAt is the zero time, is never
+// older than d.
+func OlderThan(release *Release, d time.Duration) bool {
+	if release == nil || release.PublishedAt.IsZero() {
+		return false
+	}
+	return time.Since(release.PublishedAt) > d
+}
+
+// GitHubLatestRelease is like GitHubLatest, but returns the whole metadata of
+// the release instead of only its tag.
+func GitHubLatestRelease(owner string, repo string,
+	opts ...Option) (*Release, error) {
+	return NewGitHub(owner, repo, opts...).LatestRelease(context.Background())
+}
+
+// LatestRelease returns the latest release.
+func (gh *GitHub) LatestRelease(ctx context.Context) (*Release, error) {
+	// https://developer.github.com/v3/repos/releases/#get-the-latest-release
+	// API: GET /repos/:owner/:repo/releases/latest
+	var release *Release
+	err := gh.mirrored(ctx, func(base string, cfg *config) error {
+		api_url := fmt.Sprintf("%s/repos/%s/%s/releases/latest",
+			base, gh.owner, gh.repo)
+		var err error
+		release, err = getRelease(ctx, cfg, api_url, bearerAuth,
+			gh.apiHeader())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	gh.checkRenamed(release)
+	return release, nil
+}
+
+// ListReleases queries the GitHub releases API and returns the releases of
+// owner/repo in the order provided by GitHub (newest first). Contrary to
+// GitHubLatest, the list contains also prereleases and, if the token allows
+// it, drafts.
+func ListReleases(owner string, repo string, opts ...Option) ([]Release, error) {
+	return NewGitHub(owner, repo, opts...).ListReleases(context.Background())
+}
+
+// ListReleases returns the releases, newest first.
+func (gh *GitHub) ListReleases(ctx context.Context) ([]Release, error) {
+	var releases []Release
+	err := gh.WalkReleases(ctx, func(r Release) bool {
+		releases = append(releases, r)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// WalkReleases calls fn for each release, newest first, until fn returns
+// false. It fetches the first page only, unless WithAllPages or
+// WithMaxReleases is used, in which case it fetches a page only when fn asked
+// for more releases than the previous pages provided. With WithMaxReleases(n),
+// it stops after n releases.
+func (gh *GitHub) WalkReleases(ctx context.Context, fn func(Release) bool) error {
+	// https://developer.github.com/v3/repos/releases/#list-releases
+	// API: GET /repos/:owner/:repo/releases
+	api_url := fmt.Sprintf("%s/repos/%s/%s/releases",
+		gh.cfg.apiURL(gitHubAPI), gh.owner, gh.repo)
+	limit := gh.cfg.maxItems
+	perPage := gh.cfg.perPage
+	if perPage <= 0 && limit > 0 {
+		perPage = limit
+		if perPage > 100 {
+			perPage = 100
+		}
+	}
+	if perPage > 0 {
+		api_url += fmt.Sprintf("?per_page=%d", perPage)
+	}
+
+	seen := 0
+	for api_url != "" {
+		var page []Release
+		header, err := getJSON(ctx, gh.cfg, api_url, bearerAuth, gh.apiHeader(),
+			&page)
+		if err != nil {
+			return err
+		}
+		for _, r := range page {
+			if limit > 0 && seen == limit {
+				return nil
+			}
+			seen++
+			if !fn(r) {
+				return nil
+			}
+		}
+		api_url = ""
+		if gh.cfg.allPages || (limit > 0 && seen < limit) {
+			api_url = nextLink(header)
+		}
+	}
+	return nil
+}
+
+// FilterDrafts returns the releases that are not drafts, preserving their
+// order. Drafts are visible only with a token granting push access, and must
+// never be offered as installable versions.
+func FilterDrafts(releases []Release) []Release {
+	published := make([]Release, 0, len(releases))
+	for _, r := range releases {
+		if !r.Draft {
+			published = append(published, r)
+		}
+	}
+	return published
+}
+
+// GetReleaseByTag queries the GitHub releases API and returns the release of
+// owner/repo with tag. If there is no such release, the error wraps
+// ErrNoRelease.
+func GetReleaseByTag(owner string, repo string, tag string,
+	opts ...Option) (*Release, error) {
+	return NewGitHub(owner, repo, opts...).ReleaseByTag(context.Background(),
+		tag)
+}
+
+// ReleaseByTag returns the release with tag.
+func (gh *GitHub) ReleaseByTag(ctx context.Context, tag string) (*Release, error) {
+	// https://developer.github.com/v3/repos/releases/#get-a-release-by-tag-name
+	// API: GET /repos/:owner/:repo/releases/tags/:tag
+	api_url := fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s",
+		gh.cfg.apiURL(gitHubAPI), gh.owner, gh.repo, url.PathEscape(tag))
+
+	release, err := getRelease(ctx, gh.cfg, api_url, bearerAuth, gh.apiHeader())
+	if err != nil {
+		return nil, err
+	}
+	gh.checkRenamed(release)
+	return release, nil
+}
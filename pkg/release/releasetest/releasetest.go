@@ -0,0 +1,255 @@
+// Package releasetest provides a fake GitHub API server, to test code using
+// package release, such as its retry, caching and conditional-request paths,
+// without network access.
+//
+// The server counts the requests it receives, so that a test can check, for
+// example, that N lookups with a cache made a single request:
+//
+//	srv := releasetest.NewServer()
+//	defer srv.Close()
+//	srv.SetLatest("owner", "repo", release.Release{TagName: "v1.2.3"})
+//	opts := []release.Option{release.WithBaseURL(srv.URL), ...}
+//	...
+//	if n := srv.Requests(releasetest.LatestPath("owner", "repo")); n != 1 {
+//		t.Fatalf("got %d requests, want 1", n)
+//	}
+package releasetest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/marco-m/taschino/pkg/release"
+)
+
+// Response is a canned response of Server.
+type Response struct {
+	// Status is the status code. If 0, it is 200.
+	Status int
+	// Header is added to the header of the response.
+	Header http.Header
+	// Body is encoded as JSON, unless it is a []byte, sent as is. If nil,
+	// the body is empty.
+	Body interface{}
+	// Delay is how long to wait before answering, for example to make
+	// concurrent requests overlap. The wait ends early if the client goes
+	// away.
+	Delay time.Duration
+}
+
+// NotModified returns a response with status 304 Not Modified.
+func NotModified() Response {
+	return Response{Status: http.StatusNotModified}
+}
+
+// RateLimited returns a response with status 403 and the headers of an
+// exhausted primary rate limit, which resets at reset.
+func RateLimited(reset time.Time) Response {
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	return Response{
+		Status: http.StatusForbidden,
+		Header: header,
+		Body:   map[string]string{"message": "API rate limit exceeded"},
+	}
+}
+
+// SecondaryRateLimited returns a response with status 403 of a secondary rate
+// limit, with a Retry-After header if retryAfter is not 0.
+func SecondaryRateLimited(retryAfter time.Duration) Response {
+	header := http.Header{}
+	if retryAfter > 0 {
+		header.Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+	}
+	return Response{
+		Status: http.StatusForbidden,
+		Header: header,
+		Body: map[string]string{
+			"message": "You have exceeded a secondary rate limit.",
+		},
+	}
+}
+
+// TooManyRequests returns a response with status 429 and, if retryAfter is
+// not 0, a Retry-After header.
+func TooManyRequests(retryAfter time.Duration) Response {
+	header := http.Header{}
+	if retryAfter > 0 {
+		header.Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+	}
+	return Response{Status: http.StatusTooManyRequests, Header: header}
+}
+
+// Status returns a response with status code and no body, such as 503.
+func Status(code int) Response {
+	return Response{Status: code}
+}
+
+// LatestPath returns the path of the latest release of owner/repo, as
+// requested by release.GitHubLatest.
+func LatestPath(owner string, repo string) string {
+	return fmt.Sprintf("/repos/%s/%s/releases/latest", owner, repo)
+}
+
+// ReleasesPath returns the path of the list of releases of owner/repo, as
+// requested by release.ListReleases.
+func ReleasesPath(owner string, repo string) string {
+	return fmt.Sprintf("/repos/%s/%s/releases", owner, repo)
+}
+
+// Server is a fake GitHub API, serving canned responses by path, to be used
+// with release.WithBaseURL(srv.URL). It is safe for concurrent use.
+//
+// A path has a queue of responses, served in order; the last one is then
+// served forever. A successful JSON response carries an ETag and, as GitHub,
+// the server answers 304 Not Modified to a request with a matching
+// If-None-Match. A path without responses gets 404 Not Found.
+type Server struct {
+	// URL is the base URL of the server, without trailing slash.
+	URL string
+
+	srv       *httptest.Server
+	mu        sync.Mutex
+	responses map[string][]Response
+	requests  map[string]int
+	total     int
+}
+
+// NewServer starts and returns a Server. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		responses: make(map[string][]Response),
+		requests:  make(map[string]int),
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serve))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Handle sets the responses to the requests for path, replacing the previous
+// ones. Use it to fail the first requests, for example:
+//
+//	srv.Handle(path, releasetest.Status(503), releasetest.Response{Body: r})
+func (s *Server) Handle(path string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[path] = responses
+}
+
+// SetLatest makes the server answer r as the latest release of owner/repo, and
+// the list of releases with r only.
+func (s *Server) SetLatest(owner string, repo string, r release.Release) {
+	s.Handle(LatestPath(owner, repo), Response{Body: r})
+	s.Handle(ReleasesPath(owner, repo), Response{Body: []release.Release{r}})
+}
+
+// SetReleases makes the server answer releases, which should be newest first,
+// as the list of releases of owner/repo, and the first of them, if any, as the
+// latest release.
+func (s *Server) SetReleases(owner string, repo string,
+	releases []release.Release) {
+	s.Handle(ReleasesPath(owner, repo), Response{Body: releases})
+	if len(releases) > 0 {
+		s.Handle(LatestPath(owner, repo), Response{Body: releases[0]})
+	}
+}
+
+// Requests returns the number of requests received for path, 304 responses
+// included.
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// TotalRequests returns the number of requests received for any path.
+func (s *Server) TotalRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.total
+}
+
+// Reset forgets the counts of requests, keeping the responses.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = make(map[string]int)
+	s.total = 0
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	response, ok := s.next(r.URL.Path)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Not Found"}`)
+		return
+	}
+	if response.Delay > 0 {
+		select {
+		case <-time.After(response.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	var body []byte
+	switch b := response.Body.(type) {
+	case nil:
+	case []byte:
+		body = b
+	default:
+		var err error
+		if body, err = json.Marshal(b); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	for k, vs := range response.Header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	status := response.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if status == http.StatusOK && body != nil {
+		etag := fmt.Sprintf(`"%x"`, sha256.Sum256(body))
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// next counts a request for path and returns its response.
+func (s *Server) next(path string) (Response, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.total++
+	s.requests[path]++
+	queue := s.responses[path]
+	if len(queue) == 0 {
+		return Response{}, false
+	}
+	response := queue[0]
+	if len(queue) > 1 {
+		s.responses[path] = queue[1:]
+	}
+	return response, true
+}
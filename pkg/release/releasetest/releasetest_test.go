@@ -0,0 +1,211 @@
+package releasetest_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/marco-m/taschino/pkg/release"
+	"github.com/marco-m/taschino/pkg/release/releasetest"
+)
+
+// get requests path from srv, with the header If-None-Match etag if not
+// empty, and returns the status and the ETag of the response.
+func get(t *testing.T, srv *releasetest.Server, path string,
+	etag string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest("GET", srv.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode, resp.Header.Get("ETag")
+}
+
+func TestServerQueue(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	path := releasetest.LatestPath("o", "r")
+	srv.Handle(path, releasetest.Status(503), releasetest.Status(502),
+		releasetest.Response{Body: release.Release{TagName: "v1.2.3"}})
+
+	var got []int
+	for i := 0; i < 5; i++ {
+		status, _ := get(t, srv, path, "")
+		got = append(got, status)
+	}
+
+	// In order, then the last one forever.
+	want := []int{503, 502, 200, 200, 200}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("statuses: got %v, want %v", got, want)
+		}
+	}
+	if n := srv.Requests(path); n != 5 {
+		t.Errorf("requests: got %d, want 5", n)
+	}
+}
+
+func TestServerNotFoundAndCounts(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	srv.SetLatest("o", "r", release.Release{TagName: "v1.2.3"})
+
+	status, _ := get(t, srv, "/unknown", "")
+	get(t, srv, releasetest.LatestPath("o", "r"), "")
+	get(t, srv, releasetest.ReleasesPath("o", "r"), "")
+
+	if status != http.StatusNotFound {
+		t.Errorf("unknown path: got status %d, want 404", status)
+	}
+	if n := srv.Requests("/unknown"); n != 1 {
+		t.Errorf("requests of the unknown path: got %d, want 1", n)
+	}
+	if n := srv.TotalRequests(); n != 3 {
+		t.Errorf("total requests: got %d, want 3", n)
+	}
+	srv.Reset()
+	if n := srv.TotalRequests(); n != 0 {
+		t.Errorf("total requests after Reset: got %d, want 0", n)
+	}
+	// Reset keeps the responses.
+	status, _ = get(t, srv, releasetest.LatestPath("o", "r"), "")
+	if status != http.StatusOK {
+		t.Errorf("after Reset: got status %d, want 200", status)
+	}
+}
+
+func TestServerNotModified(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	path := releasetest.LatestPath("o", "r")
+	srv.SetLatest("o", "r", release.Release{TagName: "v1.2.3"})
+
+	_, etag := get(t, srv, path, "")
+	status, _ := get(t, srv, path, etag)
+	stale, _ := get(t, srv, path, `"stale"`)
+
+	if etag == "" {
+		t.Fatal("no ETag")
+	}
+	if status != http.StatusNotModified {
+		t.Errorf("matching ETag: got status %d, want 304", status)
+	}
+	if stale != http.StatusOK {
+		t.Errorf("other ETag: got status %d, want 200", stale)
+	}
+}
+
+// cacheDir returns a new directory for release.WithCache.
+func cacheDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "taschino-cache-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestCachedLookupsMakeOneRequest(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	srv.SetLatest("o", "r", release.Release{TagName: "v1.2.3"})
+	opts := []release.Option{release.WithBaseURL(srv.URL),
+		release.WithCache(cacheDir(t), time.Hour)}
+
+	for i := 0; i < 5; i++ {
+		tag, err := release.GitHubLatest("o", "r", opts...)
+		if err != nil || tag != "v1.2.3" {
+			t.Fatalf("lookup %d: got tag %q, error %v; want v1.2.3", i, tag,
+				err)
+		}
+	}
+
+	if n := srv.Requests(releasetest.LatestPath("o", "r")); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
+
+func TestExpiredCacheRevalidates(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	srv.SetLatest("o", "r", release.Release{TagName: "v1.2.3"})
+	var statuses []int
+	// A ttl of 0 revalidates at each lookup.
+	opts := []release.Option{release.WithBaseURL(srv.URL),
+		release.WithCache(cacheDir(t), 0),
+		release.WithObserver(func(e release.Event) {
+			statuses = append(statuses, e.StatusCode)
+		})}
+
+	for i := 0; i < 2; i++ {
+		tag, err := release.GitHubLatest("o", "r", opts...)
+		if err != nil || tag != "v1.2.3" {
+			t.Fatalf("lookup %d: got tag %q, error %v; want v1.2.3", i, tag,
+				err)
+		}
+	}
+
+	if n := srv.Requests(releasetest.LatestPath("o", "r")); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+	if len(statuses) != 2 || statuses[0] != 200 || statuses[1] != 304 {
+		t.Errorf("statuses: got %v, want [200 304]", statuses)
+	}
+}
+
+func TestLatestCachedNotModified(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	srv.SetLatest("o", "r", release.Release{TagName: "v1.2.3"})
+	gh := release.NewGitHub("o", "r", release.WithBaseURL(srv.URL))
+	first, err := gh.LatestCached(context.Background(), release.CacheEntry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ETag == "" {
+		t.Fatal("no ETag")
+	}
+
+	second, err := gh.LatestCached(context.Background(), first)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Tag != "v1.2.3" || second.ETag != first.ETag {
+		t.Errorf("got %+v, want the tag and ETag of %+v", second, first)
+	}
+	if !second.FetchedAt.After(first.FetchedAt) {
+		t.Error("FetchedAt not updated")
+	}
+}
+
+func TestRetryAfterUnavailable(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	path := releasetest.LatestPath("o", "r")
+	srv.Handle(path, releasetest.Status(503),
+		releasetest.Response{Body: release.Release{TagName: "v1.2.3"}})
+
+	tag, err := release.GitHubLatest("o", "r", release.WithBaseURL(srv.URL),
+		release.WithRetry(3, time.Millisecond))
+
+	if err != nil || tag != "v1.2.3" {
+		t.Fatalf("got tag %q, error %v; want v1.2.3", tag, err)
+	}
+	if n := srv.Requests(path); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+}
@@ -0,0 +1,41 @@
+package release
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Repository returns the repository of r, as per its API URL, and true; or
+// false if r has no API URL. If the repository was renamed or transferred,
+// GitHub redirects the requests made with the old owner/repo, and Repository
+// returns the new, canonical, one: callers keying caches or configuration by
+// repository can use it to update the key.
+func (r *Release) Repository() (RepoRef, bool) {
+	// Such as https://api.github.com/repos/OWNER/REPO/releases/1234, or with
+	// a base path for GitHub Enterprise.
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return RepoRef{}, false
+	}
+	fields := strings.Split(u.Path, "/")
+	for i := 0; i+3 < len(fields); i++ {
+		if fields[i] == "repos" && fields[i+3] == "releases" {
+			return RepoRef{Owner: fields[i+1], Repo: fields[i+2]}, true
+		}
+	}
+	return RepoRef{}, false
+}
+
+// checkRenamed warns if release, fetched for gh, belongs to another
+// repository, that is, if the repository of gh was renamed.
+func (gh *GitHub) checkRenamed(release *Release) {
+	ref, ok := release.Repository()
+	if !ok {
+		return
+	}
+	if !strings.EqualFold(ref.Owner, gh.owner) ||
+		!strings.EqualFold(ref.Repo, gh.repo) {
+		gh.cfg.warn("repository renamed", "repo", gh.owner+"/"+gh.repo,
+			"new", ref.String())
+	}
+}
@@ -0,0 +1,116 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// warnings is a Logger recording its warnings, as the message followed by
+// the list of arguments.
+type warnings struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (w *warnings) Debug(msg string, args ...interface{}) {}
+
+func (w *warnings) Warn(msg string, args ...interface{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.msgs = append(w.msgs, fmt.Sprint(msg, args))
+}
+
+// renamedServer returns a server where the repository old/r was renamed to
+// new/r: GitHub redirects the requests of old/r with 301 Moved Permanently.
+func renamedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/repos/old/r/releases/latest":
+				http.Redirect(w, r, "/repos/new/r/releases/latest",
+					http.StatusMovedPermanently)
+			case "/repos/new/r/releases/latest":
+				fmt.Fprintf(w, `{"tag_name":"v1.2.3","url":"%s"}`,
+					srv.URL+"/repos/new/r/releases/1")
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLatestReleaseOfRenamedRepository(t *testing.T) {
+	srv := renamedServer(t)
+	logger := &warnings{}
+
+	release, err := NewGitHub("old", "r", WithBaseURL(srv.URL),
+		WithLogger(logger)).LatestRelease(context.Background())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.TagName != "v1.2.3" {
+		t.Errorf("tag: got %q, want v1.2.3", release.TagName)
+	}
+	ref, ok := release.Repository()
+	if !ok || ref != (RepoRef{Owner: "new", Repo: "r"}) {
+		t.Errorf("Repository: got %v, %v; want new/r, true", ref, ok)
+	}
+	want := []string{
+		fmt.Sprintf("request redirected[url %s location %s]",
+			srv.URL+"/repos/old/r/releases/latest",
+			srv.URL+"/repos/new/r/releases/latest"),
+		"repository renamed[repo old/r new new/r]",
+	}
+	if fmt.Sprint(logger.msgs) != fmt.Sprint(want) {
+		t.Errorf("warnings:\ngot  %q\nwant %q", logger.msgs, want)
+	}
+}
+
+func TestLatestReleaseNotRenamed(t *testing.T) {
+	srv := renamedServer(t)
+	logger := &warnings{}
+
+	release, err := NewGitHub("new", "r", WithBaseURL(srv.URL),
+		WithLogger(logger)).LatestRelease(context.Background())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref, _ := release.Repository(); ref != (RepoRef{Owner: "new",
+		Repo: "r"}) {
+		t.Errorf("Repository: got %v, want new/r", ref)
+	}
+	if len(logger.msgs) != 0 {
+		t.Errorf("got warnings %q, want none", logger.msgs)
+	}
+}
+
+func TestRepository(t *testing.T) {
+	tests := []struct {
+		url    string
+		want   RepoRef
+		wantOK bool
+	}{
+		{"https://api.github.com/repos/o/r/releases/1", RepoRef{"o", "r"}, true},
+		{"https://ghe.example/api/v3/repos/o/r/releases/1", RepoRef{"o", "r"},
+			true},
+		{"", RepoRef{}, false},
+		{"https://api.github.com/users/o", RepoRef{}, false},
+	}
+	for _, tc := range tests {
+		got, ok := (&Release{URL: tc.url}).Repository()
+
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("Repository of %q: got %v, %v; want %v, %v", tc.url, got,
+				ok, tc.want, tc.wantOK)
+		}
+	}
+}
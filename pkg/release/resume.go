@@ -0,0 +1,152 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DownloadAssetToFile downloads the asset called assetName of the release with
+// tag of the GitHub repository owner/repo to the file path, resuming a
+// previous partial download: if path exists, only the missing bytes are
+// requested, with an HTTP Range request, and appended to it. If the server
+// does not support ranges, the file is downloaded again from the start.
+//
+// Once done, the size of the file is checked against the size announced by the
+// server. On error, the partial file is left in place, so that calling
+// DownloadAssetToFile again resumes the download. As for DownloadAsset, use
+// ctx to bound the download.
+func DownloadAssetToFile(ctx context.Context, owner string, repo string,
+	tag string, assetName string, path string, opts ...Option) error {
+	return NewGitHub(owner, repo, opts...).DownloadAssetToFile(ctx, tag,
+		assetName, path)
+}
+
+// DownloadAssetToFile downloads the asset called assetName of the release with
+// tag to the file path, resuming a previous partial download. See the
+// function DownloadAssetToFile.
+func (gh *GitHub) DownloadAssetToFile(ctx context.Context, tag string,
+	assetName string, path string) error {
+	release, err := gh.ReleaseByTag(ctx, tag)
+	if err != nil {
+		return err
+	}
+	asset, err := release.Asset(assetName)
+	if err != nil {
+		return err
+	}
+	return gh.downloadToFile(ctx, asset, path)
+}
+
+// downloadToFile writes the contents of asset to the file path, resuming from
+// the bytes already in it.
+func (gh *GitHub) downloadToFile(ctx context.Context, asset *Asset,
+	path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	offset := fi.Size()
+	if asset.Size > 0 && offset > asset.Size {
+		// Not a prefix of this asset: start over.
+		offset = 0
+	}
+	if asset.Size > 0 && offset == asset.Size {
+		return nil
+	}
+
+	var header http.Header
+	if offset > 0 {
+		header = http.Header{}
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := gh.openAsset(ctx, asset, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var total int64
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, size, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return fmt.Errorf("download %s: %w", asset.Name, err)
+		}
+		if start != offset {
+			return fmt.Errorf("download %s: requested bytes from %d, "+
+				"got from %d", asset.Name, offset, start)
+		}
+		total = size
+	case http.StatusOK:
+		// The server ignored the Range header and sent the whole file.
+		offset = 0
+		total = resp.ContentLength
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The partial file is not shorter than the asset: it is stale.
+		resp.Body.Close()
+		if err := f.Truncate(0); err != nil {
+			return fmt.Errorf("download %s: %w", asset.Name, err)
+		}
+		f.Close()
+		return gh.downloadToFile(ctx, asset, path)
+	default:
+		return fmt.Errorf("download %s: unexpected status %s", asset.Name,
+			resp.Status)
+	}
+
+	if total < 0 && asset.Size > 0 {
+		total = asset.Size
+	}
+	if err := f.Truncate(offset); err != nil {
+		return fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	n, err := io.Copy(f, resp.Body)
+	if err != nil {
+		return fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	if total >= 0 && offset+n != total {
+		return fmt.Errorf("download %s: got %d bytes, want %d", asset.Name,
+			offset+n, total)
+	}
+	return f.Close()
+}
+
+// parseContentRange parses the Content-Range header of a 206 response, such
+// as "bytes 100-999/1000", returning the first byte position and the complete
+// length, or -1 if the length is unknown ("bytes 100-999/*").
+func parseContentRange(s string) (int64, int64, error) {
+	spec := strings.TrimPrefix(s, "bytes ")
+	slash := strings.Index(spec, "/")
+	dash := strings.Index(spec, "-")
+	if spec == s || slash < 0 || dash < 0 || dash > slash {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", s)
+	}
+	start, err := strconv.ParseInt(spec[:dash], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", s)
+	}
+	if spec[slash+1:] == "*" {
+		return start, -1, nil
+	}
+	size, err := strconv.ParseInt(spec[slash+1:], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", s)
+	}
+	return start, size, nil
+}
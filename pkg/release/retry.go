@@ -0,0 +1,89 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// retryDelay reports whether the attempt-th attempt, which failed with err,
+// should be retried and after how long.
+func (cfg *config) retryDelay(attempt int, err error) (time.Duration, bool) {
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		wait := rateLimitErr.RetryAfter
+		if wait <= 0 && rateLimitErr.Secondary {
+			// GitHub asks to wait at least one minute if it does not say how
+			// long.
+			wait = secondaryRateLimitWait
+		}
+		if wait > 0 {
+			return wait, attempt < cfg.retryAfterAttempts &&
+				wait <= cfg.retryAfterMaxWait
+		}
+	}
+	if isTransient(err) && attempt < cfg.retryAttempts {
+		return backoff(cfg.retryBaseDelay, attempt), true
+	}
+	return 0, false
+}
+
+// secondaryRateLimitWait is the wait before retrying after a secondary rate
+// limit without a Retry-After header.
+const secondaryRateLimitWait = time.Minute
+
+// isTransient reports whether err is worth retrying: a connection error or a
+// temporarily unavailable service.
+func isTransient(err error) bool {
+	var urlErr *url.Error
+	return errors.Is(err, ErrUnavailable) || errors.As(err, &urlErr)
+}
+
+// backoff returns the delay before the attempt+1-th attempt: base doubled at
+// each attempt, with a random jitter between half and the whole of it.
+func backoff(base time.Duration, attempt int) time.Duration {
+	d := base << uint(attempt-1)
+	if d <= 0 {
+		// No base delay, or overflow.
+		return base
+	}
+	half := int64(d / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// sleep waits for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseRetryAfter parses the value of a Retry-After header, either a number of
+// seconds or an HTTP date, relative to now. It returns 0 if value is empty or
+// invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
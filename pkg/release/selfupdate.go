@@ -0,0 +1,306 @@
+package release
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// SelfUpdate replaces the running executable with the binary of the latest
+// stable release (as LatestStable) of the GitHub repository owner/repo, if
+// newer than the current version. It selects the asset for the running
+// platform (as SelectAsset) and verifies its SHA-256 against the checksums
+// asset of the release (see WithChecksumsAsset) and, with WithVerifier, its
+// signature before replacing the executable. The asset must be the executable
+// itself, not an archive.
+//
+// It returns whether the update was applied and the latest version. The new
+// version runs from the next start of the program. With WithDryRun, it
+// performs all the steps but the replacement of the executable and, if they
+// succeed, returns false, the latest version and no error, filling the
+// DryRunReport of WithDryRun. An error is always a failure of a step.
+//
+// The current version is the one of WithCurrentVersion or, by default, of the
+// build information of the program.
+//
+// The replacement is atomic: the new binary is written and synced to a
+// temporary file in the same directory, which is then renamed over the
+// executable. If anything fails before (bad checksum, partial download,
+// permission error), the executable is left untouched. The previous executable
+// is kept with suffix ".old": call RollbackSelfUpdate to restore it, for
+// example if the new version fails to start, or CleanupSelfUpdate to remove
+// it. On Windows, where a running executable cannot be overwritten, the
+// running executable is renamed to ".old" and the new one takes its place.
+func SelfUpdate(ctx context.Context, owner string, repo string,
+	opts ...Option) (applied bool, newVersion string, err error) {
+	gh := NewGitHub(owner, repo, opts...)
+	if gh.cfg.requireSigned && gh.cfg.verifier == nil {
+		return false, "", fmt.Errorf("self-update: signature required, " +
+			"but no verifier configured (use WithVerifier)")
+	}
+	current := gh.cfg.currentVersion
+	if current == "" {
+		current = mainVersion()
+	}
+	if current == "" {
+		return false, "", fmt.Errorf("self-update: unknown current version " +
+			"(use WithCurrentVersion)")
+	}
+
+	release, err := latestReleaseFunc(ctx, gh, isStable, ErrNoStableRelease)
+	if err != nil {
+		return false, "", err
+	}
+	newer, err := IsNewer(current, gh.cfg.tagVersion(release.TagName))
+	if err != nil {
+		return false, "", err
+	}
+	if !newer {
+		return false, release.TagName, nil
+	}
+
+	asset, err := gh.cfg.selectAsset(release, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return false, "", err
+	}
+	checksumsName := gh.cfg.checksumsAsset
+	if checksumsName == "" {
+		checksumsName, err = findChecksumsAsset(release)
+		if err != nil {
+			return false, "", err
+		}
+	}
+	checksums, err := gh.checksums(ctx, release, checksumsName)
+	if err != nil {
+		return false, "", err
+	}
+
+	exe, err := executable()
+	if err != nil {
+		return false, "", err
+	}
+	if err := gh.install(ctx, release, asset, checksums, exe); err != nil {
+		return false, "", err
+	}
+	return !gh.cfg.dryRun, release.TagName, nil
+}
+
+// DryRunReport describes the update that SelfUpdate or SelfUpdateFromURL
+// would have applied, with WithDryRun, once all the steps before the
+// replacement of the executable succeeded.
+type DryRunReport struct {
+	// Version is the version that would have been installed.
+	Version string
+	// Asset is the name of the downloaded asset.
+	Asset string
+	// Executable is the path of the executable that would have been replaced.
+	Executable string
+	// SignatureVerified is true if the signature of the asset was verified,
+	// with WithVerifier. The checksum is always verified.
+	SignatureVerified bool
+}
+
+func (r *DryRunReport) String() string {
+	verified := "checksum verified"
+	if r.SignatureVerified {
+		verified = "checksum and signature verified"
+	}
+	return fmt.Sprintf("dry run: would replace %s with %s %s (%s)",
+		r.Executable, r.Asset, r.Version, verified)
+}
+
+// CleanupSelfUpdate removes the previous executable kept by SelfUpdate. Call
+// it once the new version is known to work. It is a no-op if there is nothing
+// to remove.
+func CleanupSelfUpdate() error {
+	exe, err := executable()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(exe + ".old"); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("self-update: %w", err)
+	}
+	return nil
+}
+
+// RollbackSelfUpdate restores the previous executable kept by SelfUpdate,
+// undoing the update. The restored version runs from the next start of the
+// program.
+func RollbackSelfUpdate() error {
+	exe, err := executable()
+	if err != nil {
+		return err
+	}
+	old := exe + ".old"
+	if _, err := os.Stat(old); err != nil {
+		return fmt.Errorf("self-update: no previous executable: %w", err)
+	}
+	if err := replaceExecutable(exe, old, false); err != nil {
+		return err
+	}
+	return nil
+}
+
+// executable returns the path of the running executable, with symlinks
+// resolved.
+func executable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("self-update: %w", err)
+	}
+	exe, err = filepath.EvalSymlinks(exe)
+	if err != nil {
+		return "", fmt.Errorf("self-update: %w", err)
+	}
+	return exe, nil
+}
+
+// findChecksumsAsset returns the name of the checksums asset of release.
+func findChecksumsAsset(release *Release) (string, error) {
+	for _, a := range release.Assets {
+		name := strings.ToLower(a.Name)
+		if name == "checksums.txt" || name == "sha256sums" ||
+			name == "sha256sums.txt" || strings.HasSuffix(name, "_checksums.txt") {
+			return a.Name, nil
+		}
+	}
+	return "", fmt.Errorf("%w: release %s has no checksums asset "+
+		"(use WithChecksumsAsset)", ErrNoAsset, release.TagName)
+}
+
+// install downloads asset of release, verifies it against checksums and
+// against its signature if WithVerifier is used, and replaces exe with it or,
+// with WithDryRun, fills the DryRunReport instead.
+func (gh *GitHub) install(ctx context.Context, release *Release, asset *Asset,
+	checksums map[string]string, exe string) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return fmt.Errorf("self-update: %w", err)
+	}
+	// Same directory, so that the final rename does not cross filesystems.
+	tmp, err := ioutil.TempFile(filepath.Dir(exe), filepath.Base(exe)+".new-*")
+	if err != nil {
+		return fmt.Errorf("self-update: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	err = gh.download(ctx, asset, io.MultiWriter(tmp, hash), nil)
+	if err == nil {
+		// Make sure the contents are on disk before the rename makes them
+		// visible, so that a crash cannot leave a truncated executable.
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("self-update: %w", err)
+	}
+	if err := verifyDigest(checksums, asset.Name, hash.Sum(nil)); err != nil {
+		return fmt.Errorf("self-update: %w", err)
+	}
+	if gh.cfg.verifier != nil {
+		if err := gh.verifyFile(ctx, release, asset.Name, tmp.Name()); err != nil {
+			return fmt.Errorf("self-update: %w", err)
+		}
+	}
+	if gh.cfg.dryRun {
+		if gh.cfg.dryRunReport != nil {
+			*gh.cfg.dryRunReport = DryRunReport{
+				Version:           release.TagName,
+				Asset:             asset.Name,
+				Executable:        exe,
+				SignatureVerified: gh.cfg.verifier != nil,
+			}
+		}
+		return nil
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0o111); err != nil {
+		return fmt.Errorf("self-update: %w", err)
+	}
+	return replaceExecutable(exe, tmp.Name(), true)
+}
+
+// replaceExecutable replaces exe with newExe. If backup is true, it keeps the
+// previous exe with suffix ".old".
+func replaceExecutable(exe string, newExe string, backup bool) error {
+	old := exe + ".old"
+	if runtime.GOOS == "windows" {
+		// A running executable cannot be overwritten, but can be renamed.
+		aside := old
+		if !backup {
+			aside = exe + ".failed"
+		}
+		_ = os.Remove(aside)
+		if err := os.Rename(exe, aside); err != nil {
+			return fmt.Errorf("self-update: %w", err)
+		}
+		if err := os.Rename(newExe, exe); err != nil {
+			// Put back the running executable.
+			_ = os.Rename(aside, exe)
+			return fmt.Errorf("self-update: %w", err)
+		}
+		return nil
+	}
+
+	if backup {
+		if err := backupFile(exe, old); err != nil {
+			return fmt.Errorf("self-update: backup: %w", err)
+		}
+	}
+	// Atomic on POSIX: exe is either the previous or the new executable.
+	if err := os.Rename(newExe, exe); err != nil {
+		return fmt.Errorf("self-update: %w", err)
+	}
+	syncDir(filepath.Dir(exe))
+	return nil
+}
+
+// backupFile makes dst a copy of src, replacing dst if it exists. It uses a
+// hard link when possible.
+func backupFile(src string, dst string) error {
+	_ = os.Remove(dst)
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC,
+		info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
+
+// syncDir flushes the directory entries of dir to disk, to make a rename
+// durable. Errors are ignored: not all platforms support it.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		d.Close()
+	}
+}
@@ -0,0 +1,208 @@
+package release
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// fakeExecutable returns the path of a file standing for the running
+// executable, with contents "old", in a new directory.
+func fakeExecutable(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "taschino-selfupdate-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.Chmod(dir, 0o755)
+		os.RemoveAll(dir)
+	})
+	exe := filepath.Join(dir, "tool")
+	if err := ioutil.WriteFile(exe, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return exe
+}
+
+// assertContents fails t if the file path does not contain want.
+func assertContents(t *testing.T, path string, want string) {
+	t.Helper()
+	got, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("%s: got %q, want %q", filepath.Base(path), got, want)
+	}
+}
+
+// assertOnlyFiles fails t if the directory dir does not contain only names.
+func assertOnlyFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, info := range infos {
+		got = append(got, info.Name())
+	}
+	if fmt.Sprint(got) != fmt.Sprint(names) {
+		t.Errorf("files: got %v, want %v", got, names)
+	}
+}
+
+// binaryServer serves body as the asset "tool", announcing length bytes.
+func binaryServer(t *testing.T, body string, length int) (*Asset,
+	map[string]string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", fmt.Sprint(length))
+			fmt.Fprint(w, body)
+		}))
+	t.Cleanup(srv.Close)
+	sum := sha256.Sum256([]byte(body))
+	asset := &Asset{Name: "tool", BrowserDownloadURL: srv.URL + "/tool"}
+	return asset, map[string]string{"tool": hex.EncodeToString(sum[:])}
+}
+
+func TestInstallReplacesExecutableAndKeepsOld(t *testing.T) {
+	exe := fakeExecutable(t)
+	asset, checksums := binaryServer(t, "new", len("new"))
+	gh := NewGitHub("o", "r")
+
+	err := gh.install(context.Background(), &Release{TagName: "v2.0.0"},
+		asset, checksums, exe)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContents(t, exe, "new")
+	assertContents(t, exe+".old", "old")
+	assertOnlyFiles(t, filepath.Dir(exe), "tool", "tool.old")
+}
+
+func TestInstallInterruptedDownload(t *testing.T) {
+	exe := fakeExecutable(t)
+	// The server announces more bytes than it sends, as on a dropped
+	// connection: the client gets an unexpected EOF.
+	asset, checksums := binaryServer(t, "ne", len("new"))
+	gh := NewGitHub("o", "r")
+
+	err := gh.install(context.Background(), &Release{TagName: "v2.0.0"},
+		asset, checksums, exe)
+
+	if err == nil {
+		t.Fatal("got no error, want a failed download")
+	}
+	assertContents(t, exe, "old")
+	assertOnlyFiles(t, filepath.Dir(exe), "tool")
+}
+
+func TestInstallReadOnlyDirectory(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("directory permissions are not enforced")
+	}
+	exe := fakeExecutable(t)
+	asset, checksums := binaryServer(t, "new", len("new"))
+	if err := os.Chmod(filepath.Dir(exe), 0o555); err != nil {
+		t.Fatal(err)
+	}
+	gh := NewGitHub("o", "r")
+
+	err := gh.install(context.Background(), &Release{TagName: "v2.0.0"},
+		asset, checksums, exe)
+
+	if err == nil {
+		t.Fatal("got no error, want a permission failure")
+	}
+	assertContents(t, exe, "old")
+	assertOnlyFiles(t, filepath.Dir(exe), "tool")
+}
+
+func TestReplaceExecutableFailedRename(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the running executable is renamed aside on Windows")
+	}
+	exe := fakeExecutable(t)
+	missing := filepath.Join(filepath.Dir(exe), "missing")
+
+	err := replaceExecutable(exe, missing, true)
+
+	if err == nil {
+		t.Fatal("got no error, want a failed rename")
+	}
+	assertContents(t, exe, "old")
+	// The backup is made before the rename, and is the previous executable.
+	assertContents(t, exe+".old", "old")
+}
+
+func TestReplaceExecutableRestoresOld(t *testing.T) {
+	exe := fakeExecutable(t)
+	if err := ioutil.WriteFile(exe+".new", []byte("new"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := replaceExecutable(exe, exe+".new", true); err != nil {
+		t.Fatal(err)
+	}
+
+	// As RollbackSelfUpdate.
+	err := replaceExecutable(exe, exe+".old", false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContents(t, exe, "old")
+	if runtime.GOOS != "windows" {
+		assertOnlyFiles(t, filepath.Dir(exe), "tool")
+	}
+}
+
+func TestInstallDryRun(t *testing.T) {
+	exe := fakeExecutable(t)
+	asset, checksums := binaryServer(t, "new", len("new"))
+	var report DryRunReport
+	gh := NewGitHub("o", "r", WithDryRun(&report))
+
+	err := gh.install(context.Background(), &Release{TagName: "v2.0.0"},
+		asset, checksums, exe)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DryRunReport{Version: "v2.0.0", Asset: "tool", Executable: exe}
+	if report != want {
+		t.Errorf("report: got %+v, want %+v", report, want)
+	}
+	assertContents(t, exe, "old")
+	assertOnlyFiles(t, filepath.Dir(exe), "tool")
+}
+
+func TestInstallDryRunFailure(t *testing.T) {
+	exe := fakeExecutable(t)
+	asset, _ := binaryServer(t, "new", len("new"))
+	var report DryRunReport
+	gh := NewGitHub("o", "r", WithDryRun(&report))
+
+	err := gh.install(context.Background(), &Release{TagName: "v2.0.0"},
+		asset, map[string]string{"tool": "00"}, exe)
+
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("got error %v, want ErrChecksumMismatch", err)
+	}
+	if report != (DryRunReport{}) {
+		t.Errorf("report: got %+v, want it empty", report)
+	}
+	assertContents(t, exe, "old")
+}
@@ -0,0 +1,118 @@
+package release
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"runtime"
+	"strings"
+	"text/template"
+)
+
+// SelfUpdateFromURL is like SelfUpdate, but for programs distributed from a
+// plain HTTP server instead of GitHub releases. It replaces the running
+// executable with the binary of the latest version, if newer than currentV.
+//
+// versionURL must return the latest version, such as "1.2.3" or "v1.2.3",
+// optionally surrounded by whitespace. binaryURLTemplate is expanded with
+// text/template to the URL of the binary for the running platform, with
+// fields .Version (as returned by versionURL), .OS, .Arch (runtime.GOOS and
+// runtime.GOARCH) and .Ext (".exe" on Windows, empty otherwise), such as
+// "https://example.com/dl/{{.Version}}/tool-{{.OS}}-{{.Arch}}{{.Ext}}".
+//
+// The binary is verified against the checksums file at its URL with suffix
+// ".sha256", in the format of ParseChecksums (as written by sha256sum) and
+// listing the binary under the last element of its URL path; with
+// WithVerifier, it is also verified against the signature at its URL with
+// suffix ".sig". The replacement, and WithDryRun, are the same as SelfUpdate.
+//
+// Only the token of WithToken, if any, is sent; the GitHub token of the
+// environment is not.
+func SelfUpdateFromURL(ctx context.Context, versionURL string,
+	binaryURLTemplate string, currentV string,
+	opts ...Option) (applied bool, err error) {
+	tmpl, err := template.New("binary").Parse(binaryURLTemplate)
+	if err != nil {
+		return false, fmt.Errorf("self-update: binary URL template: %w", err)
+	}
+	// Not NewGitHub: the environment token is for GitHub only.
+	gh := &GitHub{cfg: newConfig(opts)}
+	if gh.cfg.requireSigned && gh.cfg.verifier == nil {
+		return false, fmt.Errorf("self-update: signature required, " +
+			"but no verifier configured (use WithVerifier)")
+	}
+
+	var buf bytes.Buffer
+	versionAsset := &Asset{Name: "version", BrowserDownloadURL: versionURL}
+	if err := gh.download(ctx, versionAsset, &buf, nil); err != nil {
+		return false, fmt.Errorf("self-update: %w", err)
+	}
+	latest := strings.TrimSpace(buf.String())
+	c, err := gh.cfg.comparator.Compare(currentV, latest)
+	if err != nil {
+		return false, err
+	}
+	if c >= 0 {
+		return false, nil
+	}
+
+	buf.Reset()
+	ext := ""
+	if runtime.GOOS == "windows" {
+		ext = ".exe"
+	}
+	err = tmpl.Execute(&buf, struct{ Version, OS, Arch, Ext string }{
+		latest, runtime.GOOS, runtime.GOARCH, ext})
+	if err != nil {
+		return false, fmt.Errorf("self-update: binary URL template: %w", err)
+	}
+	release, err := urlRelease(latest, buf.String())
+	if err != nil {
+		return false, err
+	}
+	asset := &release.Assets[0]
+
+	checksums, err := gh.checksums(ctx, release, release.Assets[1].Name)
+	if err != nil {
+		return false, fmt.Errorf("self-update: %w", err)
+	}
+	exe, err := executable()
+	if err != nil {
+		return false, err
+	}
+	if err := gh.install(ctx, release, asset, checksums, exe); err != nil {
+		return false, err
+	}
+	return !gh.cfg.dryRun, nil
+}
+
+// urlRelease returns the release of version made of the binary at binaryURL,
+// and of its checksums and signature files, so that SelfUpdateFromURL can
+// reuse the machinery of SelfUpdate.
+func urlRelease(version string, binaryURL string) (*Release, error) {
+	u, err := url.Parse(binaryURL)
+	if err != nil {
+		return nil, fmt.Errorf("self-update: binary URL: %w", err)
+	}
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return nil, fmt.Errorf("self-update: binary URL without file name: %s",
+			binaryURL)
+	}
+	sibling := func(suffix string) string {
+		v := *u
+		v.Path += suffix
+		v.RawPath = ""
+		return v.String()
+	}
+	return &Release{
+		TagName: version,
+		Assets: []Asset{
+			{Name: name, BrowserDownloadURL: binaryURL},
+			{Name: name + ".sha256", BrowserDownloadURL: sibling(".sha256")},
+			{Name: name + ".sig", BrowserDownloadURL: sibling(".sig")},
+		},
+	}, nil
+}
@@ -0,0 +1,177 @@
+package release
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// Verifier verifies a detached signature, such as the ".sig" or ".asc" asset
+// accompanying a release binary.
+//
+// This package does not depend on an OpenPGP implementation; to verify GPG
+// signatures, wrap for example openpgp.CheckArmoredDetachedSignature of
+// golang.org/x/crypto/openpgp (or of a maintained fork) with VerifierFunc.
+type Verifier interface {
+	// Verify returns nil if signature is a valid signature of data.
+	Verify(data io.Reader, signature []byte) error
+}
+
+// VerifierFunc adapts a function to the Verifier interface.
+type VerifierFunc func(data io.Reader, signature []byte) error
+
+// Verify calls f(data, signature).
+func (f VerifierFunc) Verify(data io.Reader, signature []byte) error {
+	return f(data, signature)
+}
+
+// Ed25519Verifier returns a Verifier of Ed25519 signatures made with the
+// private key corresponding to publicKey. The signature is either the raw 64
+// bytes or their standard base64 encoding.
+func Ed25519Verifier(publicKey ed25519.PublicKey) Verifier {
+	return VerifierFunc(func(data io.Reader, signature []byte) error {
+		if len(publicKey) != ed25519.PublicKeySize {
+			return fmt.Errorf("ed25519: invalid public key size %d",
+				len(publicKey))
+		}
+		sig := signature
+		if len(sig) != ed25519.SignatureSize {
+			decoded, err := base64.StdEncoding.DecodeString(
+				string(bytes.TrimSpace(signature)))
+			if err != nil {
+				return fmt.Errorf("ed25519: invalid signature encoding: %w", err)
+			}
+			sig = decoded
+		}
+		message, err := ioutil.ReadAll(data)
+		if err != nil {
+			return err
+		}
+		if !ed25519.Verify(publicKey, message, sig) {
+			return errors.New("ed25519: invalid signature")
+		}
+		return nil
+	})
+}
+
+// SignatureError is returned when the signature of an asset is not valid. It
+// matches ErrBadSignature with errors.Is and unwraps to the error of the
+// Verifier.
+type SignatureError struct {
+	Asset string
+	Err   error
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrBadSignature, e.Asset, e.Err)
+}
+
+func (e *SignatureError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrBadSignature.
+func (e *SignatureError) Is(target error) bool {
+	return target == ErrBadSignature
+}
+
+// VerifySignature downloads the asset called assetName of the release with
+// tag of the GitHub repository owner/repo and verifies it with v against the
+// detached signature attached to the same release as asset sigAssetName. If
+// the signature is not valid, the error is a *SignatureError.
+func VerifySignature(ctx context.Context, owner string, repo string,
+	tag string, assetName string, sigAssetName string, v Verifier,
+	opts ...Option) error {
+	gh := NewGitHub(owner, repo, opts...)
+	release, err := gh.ReleaseByTag(ctx, tag)
+	if err != nil {
+		return err
+	}
+	asset, err := release.Asset(assetName)
+	if err != nil {
+		return err
+	}
+	sig, err := gh.signature(ctx, release, sigAssetName)
+	if err != nil {
+		return err
+	}
+
+	// Stream the asset to the verifier, without holding it in memory.
+	pr, pw := io.Pipe()
+	downloadErr := make(chan error, 1)
+	go func() {
+		err := gh.download(ctx, asset, pw, nil)
+		pw.CloseWithError(err)
+		downloadErr <- err
+	}()
+	verifyErr := v.Verify(pr, sig)
+	// Unblock the download if the verifier did not read everything.
+	pr.Close()
+	if err := <-downloadErr; err != nil && !errors.Is(err, io.ErrClosedPipe) {
+		return err
+	}
+	if verifyErr != nil {
+		return &SignatureError{Asset: assetName, Err: verifyErr}
+	}
+	return nil
+}
+
+// signature downloads the asset called name of release.
+func (gh *GitHub) signature(ctx context.Context, release *Release,
+	name string) ([]byte, error) {
+	asset, err := release.Asset(name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := gh.download(ctx, asset, &buf, nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// verifyFile verifies the file at path, the downloaded asset called
+// assetName of release, with the Verifier of WithVerifier, against the
+// signature asset of release.
+func (gh *GitHub) verifyFile(ctx context.Context, release *Release,
+	assetName string, path string) error {
+	sigName, err := findSignatureAsset(release, assetName)
+	if err != nil {
+		return err
+	}
+	sig, err := gh.signature(ctx, release, sigName)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := gh.cfg.verifier.Verify(f, sig); err != nil {
+		return &SignatureError{Asset: assetName, Err: err}
+	}
+	return nil
+}
+
+// signatureSuffixes are the suffixes of the name of the signature asset of an
+// asset, by preference.
+var signatureSuffixes = []string{".sig", ".asc"}
+
+// findSignatureAsset returns the name of the signature asset of the asset
+// called assetName of release.
+func findSignatureAsset(release *Release, assetName string) (string, error) {
+	for _, suffix := range signatureSuffixes {
+		if _, err := release.Asset(assetName + suffix); err == nil {
+			return assetName + suffix, nil
+		}
+	}
+	return "", fmt.Errorf("%w: release %s has no signature for %s",
+		ErrNoAsset, release.TagName, assetName)
+}
@@ -0,0 +1,73 @@
+package release
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// flightGroup coalesces concurrent calls with the same key into one, as
+// golang.org/x/sync/singleflight, sharing its result with all the callers.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// flight is a call of flightGroup.do in progress.
+type flight struct {
+	done   chan struct{}
+	result latestResult
+	err    error
+}
+
+// latestFlights coalesces the lookups of WithSingleflight.
+var latestFlights flightGroup
+
+// do calls fn, unless a call with the same key is in progress, and returns its
+// result, whether it comes from this call or from the one in progress. As for
+// CachingProvider, fn is shared by many callers, so it is not bound to the
+// context of any of them: canceling ctx stops waiting for fn, but not fn.
+func (g *flightGroup) do(ctx context.Context, key string,
+	fn func() (latestResult, error)) (latestResult, error) {
+	g.mu.Lock()
+	cl, ok := g.calls[key]
+	if !ok {
+		if g.calls == nil {
+			g.calls = make(map[string]*flight)
+		}
+		cl = &flight{done: make(chan struct{})}
+		g.calls[key] = cl
+		go func() {
+			cl.result, cl.err = fn()
+			g.mu.Lock()
+			delete(g.calls, key)
+			g.mu.Unlock()
+			close(cl.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-cl.done:
+		return cl.result, cl.err
+	case <-ctx.Done():
+		return latestResult{}, ctx.Err()
+	}
+}
+
+// detached is a context with the values of its parent, but without its
+// deadline and cancellation, for a call of flightGroup.do that outlives the
+// caller that started it.
+type detached struct {
+	parent context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detached) Done() <-chan struct{} { return nil }
+
+func (detached) Err() error { return nil }
+
+func (d detached) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
@@ -0,0 +1,126 @@
+package release_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marco-m/taschino/pkg/release"
+	"github.com/marco-m/taschino/pkg/release/releasetest"
+)
+
+// concurrentLatest calls Latest of owner/repo from n goroutines at once and
+// returns the tags and errors they got.
+func concurrentLatest(n int, opts ...release.Option) ([]string, []error) {
+	tags := make([]string, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tags[i], errs[i] = release.NewGitHub("o", "r", opts...).
+				Latest(context.Background())
+		}(i)
+	}
+	wg.Wait()
+	return tags, errs
+}
+
+func TestSingleflightSharesRequest(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	// The delay makes the lookups overlap.
+	srv.Handle(releasetest.LatestPath("o", "r"), releasetest.Response{
+		Body:  release.Release{TagName: "v1.2.3"},
+		Delay: 200 * time.Millisecond,
+	})
+	var mu sync.Mutex
+	var events []release.Event
+
+	tags, errs := concurrentLatest(10, release.WithBaseURL(srv.URL),
+		release.WithSingleflight(), release.WithObserver(func(e release.Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e)
+		}))
+
+	if n := srv.Requests(releasetest.LatestPath("o", "r")); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+	for i := range tags {
+		if errs[i] != nil || tags[i] != "v1.2.3" {
+			t.Errorf("lookup %d: got tag %q, error %v; want v1.2.3", i, tags[i],
+				errs[i])
+		}
+	}
+	if len(events) != 10 {
+		t.Fatalf("got %d events, want 10", len(events))
+	}
+	for i, e := range events {
+		if e.StatusCode != http.StatusOK || e.CacheHit {
+			t.Errorf("event %d: got StatusCode %d, CacheHit %v; want 200, false",
+				i, e.StatusCode, e.CacheHit)
+		}
+	}
+}
+
+func TestSingleflightSharesError(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	srv.Handle(releasetest.LatestPath("o", "r"), releasetest.Response{
+		Status: http.StatusServiceUnavailable,
+		Delay:  200 * time.Millisecond,
+	})
+
+	_, errs := concurrentLatest(10, release.WithBaseURL(srv.URL),
+		release.WithSingleflight())
+
+	if n := srv.Requests(releasetest.LatestPath("o", "r")); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+	for i, err := range errs {
+		if !errors.Is(err, release.ErrUnavailable) {
+			t.Errorf("lookup %d: got error %v, want ErrUnavailable", i, err)
+		}
+	}
+}
+
+func TestSingleflightCanceledWaiter(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	srv.Handle(releasetest.LatestPath("o", "r"), releasetest.Response{
+		Body:  release.Release{TagName: "v1.2.3"},
+		Delay: 200 * time.Millisecond,
+	})
+	gh := release.NewGitHub("o", "r", release.WithBaseURL(srv.URL),
+		release.WithSingleflight())
+	ctx, cancel := context.WithTimeout(context.Background(),
+		20*time.Millisecond)
+	defer cancel()
+	done := make(chan struct{})
+	var tag string
+	var err error
+	go func() {
+		defer close(done)
+		tag, err = gh.Latest(context.Background())
+	}()
+
+	_, canceledErr := gh.Latest(ctx)
+	<-done
+
+	if !errors.Is(canceledErr, context.DeadlineExceeded) {
+		t.Errorf("canceled lookup: got error %v, want DeadlineExceeded",
+			canceledErr)
+	}
+	// The shared request goes on for the other lookup.
+	if err != nil || tag != "v1.2.3" {
+		t.Errorf("got tag %q, error %v; want v1.2.3", tag, err)
+	}
+	if n := srv.Requests(releasetest.LatestPath("o", "r")); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
@@ -0,0 +1,110 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// GitHubLatestTag queries the GitHub tags API and returns the highest tag of
+// owner/repo by semver precedence, skipping tags that are not valid semver.
+// The leading "v" is optional, as in Compare, and the prefix of WithTagPrefix
+// is removed before comparing; the returned tag is unchanged.
+// It is the fallback for projects that push tags without creating GitHub
+// Releases, for which GitHubLatest fails with ErrNoRelease. If there is no
+// semver tag, the error wraps ErrNoRelease. Only the first page of tags is
+// listed unless WithAllPages is used.
+func GitHubLatestTag(owner string, repo string, opts ...Option) (string, error) {
+	gh := NewGitHub(owner, repo, opts...)
+	tags, err := gh.ListTags(context.Background())
+	if err != nil {
+		return "", err
+	}
+	best, bestV := "", ""
+	for _, tag := range tags {
+		v := normalize(gh.cfg.tagVersion(tag))
+		if !semver.IsValid(v) {
+			continue
+		}
+		if bestV == "" || semver.Compare(v, bestV) > 0 {
+			best, bestV = tag, v
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("%w for %s/%s: no semver tag among %d tags",
+			ErrNoRelease, owner, repo, len(tags))
+	}
+	return best, nil
+}
+
+// ListTags returns the names of the git tags, in the order provided by GitHub.
+func (gh *GitHub) ListTags(ctx context.Context) ([]string, error) {
+	// https://developer.github.com/v3/repos/#list-repository-tags
+	// API: GET /repos/:owner/:repo/tags
+	api_url := fmt.Sprintf("%s/repos/%s/%s/tags",
+		gh.cfg.apiURL(gitHubAPI), gh.owner, gh.repo)
+	if gh.cfg.perPage > 0 {
+		api_url += fmt.Sprintf("?per_page=%d", gh.cfg.perPage)
+	}
+
+	type Tag struct {
+		Name string `json:"name"`
+	}
+	var names []string
+	for api_url != "" {
+		var page []Tag
+		header, err := getJSON(ctx, gh.cfg, api_url, bearerAuth, gh.apiHeader(),
+			&page)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range page {
+			names = append(names, tag.Name)
+		}
+		api_url = ""
+		if gh.cfg.allPages {
+			api_url = nextLink(header)
+		}
+	}
+	return names, nil
+}
+
+// TagMessage returns the message of the annotated git tag, or the empty string
+// if tag is a lightweight tag, which has no message. If there is no such tag,
+// the error wraps ErrNoRelease.
+func (gh *GitHub) TagMessage(ctx context.Context, tag string) (string, error) {
+	// https://docs.github.com/en/rest/git/refs#get-a-reference
+	// API: GET /repos/:owner/:repo/git/ref/tags/:tag
+	api_url := fmt.Sprintf("%s/repos/%s/%s/git/ref/tags/%s",
+		gh.cfg.apiURL(gitHubAPI), gh.owner, gh.repo, url.PathEscape(tag))
+	var ref struct {
+		Object struct {
+			Type string `json:"type"`
+			SHA  string `json:"sha"`
+		} `json:"object"`
+	}
+	if _, err := getJSON(ctx, gh.cfg, api_url, bearerAuth, gh.apiHeader(),
+		&ref); err != nil {
+		return "", err
+	}
+	if ref.Object.Type != "tag" {
+		// A lightweight tag points directly to a commit.
+		return "", nil
+	}
+
+	// https://docs.github.com/en/rest/git/tags#get-a-tag
+	// API: GET /repos/:owner/:repo/git/tags/:sha
+	api_url = fmt.Sprintf("%s/repos/%s/%s/git/tags/%s",
+		gh.cfg.apiURL(gitHubAPI), gh.owner, gh.repo, ref.Object.SHA)
+	var annotated struct {
+		Message string `json:"message"`
+	}
+	if _, err := getJSON(ctx, gh.cfg, api_url, bearerAuth, gh.apiHeader(),
+		&annotated); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(annotated.Message), nil
+}
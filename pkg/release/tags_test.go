@@ -0,0 +1,63 @@
+package release_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marco-m/taschino/pkg/release"
+	"github.com/marco-m/taschino/pkg/release/releasetest"
+)
+
+// tag is an entry of the tags API.
+type tag struct {
+	Name string `json:"name"`
+}
+
+// tagsPath is the path of the tags of o/r.
+const tagsPath = "/repos/o/r/tags"
+
+func TestGitHubLatestTag(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []tag
+		opts []release.Option
+		want string
+	}{
+		{"with v", []tag{{"v1.2.0"}, {"v1.10.0"}, {"v1.9.0"}}, nil, "v1.10.0"},
+		{"without v", []tag{{"1.2.0"}, {"1.10.0"}, {"nightly"}}, nil,
+			"1.10.0"},
+		{"mixed", []tag{{"v1.2.0"}, {"1.3.0"}, {"v1.2.9"}}, nil, "1.3.0"},
+		{"prefix", []tag{{"release-1.2.0"}, {"release-1.10.0"},
+			{"release-1.9.0"}}, []release.Option{
+			release.WithTagPrefix("release-")}, "release-1.10.0"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := releasetest.NewServer()
+			defer srv.Close()
+			srv.Handle(tagsPath, releasetest.Response{Body: tc.tags})
+
+			got, err := release.GitHubLatestTag("o", "r",
+				append(tc.opts, release.WithBaseURL(srv.URL))...)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGitHubLatestTagNoSemver(t *testing.T) {
+	srv := releasetest.NewServer()
+	defer srv.Close()
+	srv.Handle(tagsPath, releasetest.Response{Body: []tag{{"nightly"}}})
+
+	_, err := release.GitHubLatestTag("o", "r", release.WithBaseURL(srv.URL))
+
+	if !errors.Is(err, release.ErrNoRelease) {
+		t.Errorf("got error %v, want ErrNoRelease", err)
+	}
+}
@@ -0,0 +1,97 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// UpdateResult is the result of CheckForUpdate. In JSON, its fields are in
+// snake case, such as "update_available", and Delta is as documented there.
+type UpdateResult struct {
+	// Current is the installed version, as passed to CheckForUpdate.
+	Current string `json:"current"`
+	// Latest is the tag of the latest release, as returned by GitHubLatest.
+	Latest string `json:"latest"`
+	// UpdateAvailable is true if Latest is newer than Current.
+	UpdateAvailable bool `json:"update_available"`
+	// Downgrade is true if Latest is older than Current, as IsDowngrade:
+	// there is no update, and Latest should not be offered as one.
+	Downgrade bool `json:"downgrade"`
+	// Comparable is false if Latest is not a valid semver, so that Current
+	// and Latest could not be compared; UpdateAvailable is then false and
+	// Delta is the zero value.
+	Comparable bool `json:"comparable"`
+	// Delta is the detailed comparison of Current with Latest, as
+	// CompareDetailed.
+	Delta Delta `json:"delta"`
+	// CheckedAt is when Latest was fetched from the GitHub API. With
+	// WithCache, if FromCache, it is when the cached value was fetched, by a
+	// previous lookup: time.Since(CheckedAt) is the age of Latest.
+	CheckedAt time.Time `json:"checked_at"`
+	// FromCache is true if Latest comes from the cache of WithCache, without
+	// asking the GitHub API.
+	FromCache bool `json:"from_cache"`
+}
+
+// CheckForUpdate tells whether there is a release of the GitHub repository
+// owner/repo newer than currentV, and which. It returns an error if the lookup
+// fails or if currentV is not a valid semver; a latest tag that is not a valid
+// semver is not an error, but an UpdateResult with Comparable false.
+//
+// With WithNoReleaseAsUpToDate, a repository without releases (ErrNoRelease)
+// is not an error either, but an UpdateResult with UpdateAvailable false and
+// Latest empty. The option applies only to CheckForUpdate: the lower-level
+// lookups, such as GitHubLatest, keep returning ErrNoRelease.
+//
+// As IsNewer, an empty currentV, for a fresh install, is older than any valid
+// latest tag: UpdateAvailable is true and Delta is a MajorChange.
+func CheckForUpdate(owner string, repo string, currentV string,
+	opts ...Option) (*UpdateResult, error) {
+	return NewGitHub(owner, repo, opts...).CheckForUpdate(context.Background(),
+		currentV)
+}
+
+// CheckForUpdate tells whether there is a release newer than currentV. See the
+// function CheckForUpdate.
+func (gh *GitHub) CheckForUpdate(ctx context.Context,
+	currentV string) (*UpdateResult, error) {
+	if currentV != "" {
+		if _, err := validVersion(currentV, "installed"); err != nil {
+			return nil, err
+		}
+	}
+	var lookup latestResult
+	if err := gh.observeLatest(ctx, &lookup); err != nil {
+		if gh.cfg.noReleaseOK && errors.Is(err, ErrNoRelease) {
+			return &UpdateResult{Current: currentV, CheckedAt: time.Now()}, nil
+		}
+		return nil, err
+	}
+	res := &UpdateResult{
+		Current:   currentV,
+		Latest:    lookup.tag,
+		CheckedAt: lookup.checkedAt,
+		FromCache: lookup.fromCache,
+	}
+	latestV := gh.cfg.tagVersion(lookup.tag)
+	if currentV == "" {
+		if _, err := validVersion(latestV, "latest"); err != nil {
+			return res, nil
+		}
+		res.Comparable = true
+		res.Delta = Delta{Direction: -1, Change: MajorChange}
+		res.UpdateAvailable = true
+		return res, nil
+	}
+	delta, err := CompareDetailed(currentV, latestV)
+	if err != nil {
+		// currentV is valid, so it is latest that is not.
+		return res, nil
+	}
+	res.Comparable = true
+	res.Delta = delta
+	res.UpdateAvailable = delta.Direction < 0
+	res.Downgrade = delta.Direction > 0
+	return res, nil
+}
@@ -0,0 +1,129 @@
+package release_test
+
+import (
+	"testing"
+
+	"github.com/marco-m/taschino/pkg/release"
+	"github.com/marco-m/taschino/pkg/release/releasetest"
+)
+
+func TestIsDowngrade(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		latest  string
+		want    bool
+	}{
+		{"equal", "v1.2.3", "v1.2.3", false},
+		{"equal with mixed prefixes", "1.2.3", "v1.2.3", false},
+		{"update", "v1.2.3", "v1.3.0", false},
+		{"newer local build", "v1.3.0-dev.1", "v1.2.3", true},
+		{"yanked release", "v1.3.0", "v1.2.3", true},
+		{"prerelease of latest", "v1.2.3-rc.1", "v1.2.3", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := release.IsDowngrade(tc.current, tc.latest)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("IsDowngrade(%q, %q): got %v, want %v", tc.current,
+					tc.latest, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsDowngradeInvalid(t *testing.T) {
+	if _, err := release.IsDowngrade("v1.2.3", "nightly"); err == nil {
+		t.Error("got no error for an invalid latest version")
+	}
+}
+
+func TestCheckForUpdateDowngrade(t *testing.T) {
+	tests := []struct {
+		name          string
+		current       string
+		latest        string
+		wantAvailable bool
+		wantDowngrade bool
+	}{
+		{"equal", "v1.2.3", "v1.2.3", false, false},
+		{"update", "v1.2.3", "v1.3.0", true, false},
+		{"newer local build", "v1.4.0-dev.1", "v1.3.0", false, true},
+		// v1.3.0 was installed, then its release deleted: the latest
+		// release is again the previous one.
+		{"yanked release", "v1.3.0", "v1.2.3", false, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := releasetest.NewServer()
+			defer srv.Close()
+			srv.SetLatest("o", "r", release.Release{TagName: tc.latest})
+
+			res, err := release.CheckForUpdate("o", "r", tc.current,
+				release.WithBaseURL(srv.URL))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Latest != tc.latest || !res.Comparable {
+				t.Errorf("got Latest %q, Comparable %v; want %q, true",
+					res.Latest, res.Comparable, tc.latest)
+			}
+			if res.UpdateAvailable != tc.wantAvailable {
+				t.Errorf("UpdateAvailable: got %v, want %v",
+					res.UpdateAvailable, tc.wantAvailable)
+			}
+			if res.Downgrade != tc.wantDowngrade {
+				t.Errorf("Downgrade: got %v, want %v", res.Downgrade,
+					tc.wantDowngrade)
+			}
+		})
+	}
+}
+
+func TestCheckForUpdateEmptyVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		latest  string
+		wantErr bool
+	}{
+		{"empty current", "", "v1.2.3", false},
+		// The API answers a release without tag_name.
+		{"empty latest", "v1.2.3", "", true},
+		{"both empty", "", "", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := releasetest.NewServer()
+			defer srv.Close()
+			srv.SetLatest("o", "r", release.Release{TagName: tc.latest})
+
+			res, err := release.CheckForUpdate("o", "r", tc.current,
+				release.WithBaseURL(srv.URL))
+
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", res)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !res.UpdateAvailable || !res.Comparable || res.Downgrade {
+				t.Errorf("got UpdateAvailable %v, Comparable %v, Downgrade %v;"+
+					" want true, true, false", res.UpdateAvailable,
+					res.Comparable, res.Downgrade)
+			}
+			want := release.Delta{Direction: -1, Change: release.MajorChange}
+			if res.Delta != want {
+				t.Errorf("Delta: got %+v, want %+v", res.Delta, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package release
+
+import "runtime/debug"
+
+// modulePath is the module path of this package, used to find its version in
+// the build information of the program.
+const modulePath = "github.com/marco-m/taschino"
+
+// mainVersion returns the version of the main module of the program, as
+// recorded in its build information (that is, when built with
+// "go install module@version"), or "" when not available.
+func mainVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "(devel)" {
+		return ""
+	}
+	return info.Main.Version
+}
+
+// defaultUserAgent returns "taschino/<version>", where version is the version
+// of this module as recorded in the build information of the program, or
+// "devel" when not available.
+func defaultUserAgent() string {
+	version := "devel"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Path == modulePath && info.Main.Version != "(devel)" {
+			version = info.Main.Version
+		}
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				version = dep.Version
+			}
+		}
+	}
+	return "taschino/" + version
+}
@@ -0,0 +1,138 @@
+package release
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"runtime"
+)
+
+// DownloadOpts describes the asset to download with DownloadAndVerify and how
+// to verify it.
+type DownloadOpts struct {
+	// Owner and Repo are the GitHub repository.
+	Owner string
+	Repo  string
+	// Tag is the tag of the release. If empty, it is the latest release, as
+	// GitHubLatest.
+	Tag string
+	// AssetName is the name of the asset. If empty, the asset is selected by
+	// SelectAsset for GOOS and GOARCH, with the exclusions of
+	// WithAssetExclusions among Options, if any.
+	AssetName string
+	// GOOS and GOARCH are the platform of the asset selected by SelectAsset,
+	// by default runtime.GOOS and runtime.GOARCH.
+	GOOS   string
+	GOARCH string
+	// ChecksumsAsset is the name of the checksums asset. If empty, it is the
+	// one of WithChecksumsAsset among Options or, as for SelfUpdate, found
+	// by name, if any (see DownloadAndVerify).
+	ChecksumsAsset string
+	// Verifier, if not nil, verifies the signature of the asset, as with
+	// WithVerifier, which it overrides.
+	Verifier Verifier
+	// Dir is the directory of the downloaded file, by default the directory
+	// of ioutil.TempFile.
+	Dir string
+	// Options are the options of the requests, such as WithToken.
+	Options []Option
+}
+
+// DownloadAndVerify downloads the asset described by opts to a new temporary
+// file, verifies its SHA-256 against the checksums asset of the release and,
+// with a Verifier, its signature, and returns the path of the file. The caller
+// owns the file, and should move or remove it.
+//
+// The checksums asset is required, unless there is a Verifier: then, if it is
+// not named by opts or WithChecksumsAsset and the release has none, the asset
+// is verified by its signature only. A named checksums asset missing from the
+// release is always an error.
+//
+// On any failure, the file is removed and the returned path is empty: a
+// returned path is always a fully verified asset. Canceling ctx stops the
+// download. See VerifyChecksum and VerifySignature for the errors.
+func DownloadAndVerify(ctx context.Context, opts DownloadOpts) (path string,
+	err error) {
+	gh := NewGitHub(opts.Owner, opts.Repo, opts.Options...)
+	if opts.Verifier != nil {
+		gh.cfg.verifier = opts.Verifier
+	}
+	var release *Release
+	if opts.Tag == "" {
+		release, err = gh.LatestRelease(ctx)
+	} else {
+		release, err = gh.ReleaseByTag(ctx, opts.Tag)
+	}
+	if err != nil {
+		return "", err
+	}
+	asset, err := gh.downloadAsset(release, opts)
+	if err != nil {
+		return "", err
+	}
+	checksumsName := opts.ChecksumsAsset
+	if checksumsName == "" {
+		checksumsName = gh.cfg.checksumsAsset
+	}
+	if checksumsName == "" {
+		checksumsName, err = findChecksumsAsset(release)
+		if err != nil && gh.cfg.verifier == nil {
+			return "", err
+		}
+	}
+	// Without checksums asset, the signature suffices.
+	var checksums map[string]string
+	if checksumsName != "" {
+		if checksums, err = gh.checksums(ctx, release, checksumsName); err != nil {
+			return "", err
+		}
+	}
+
+	tmp, err := ioutil.TempFile(opts.Dir, "taschino-*-"+asset.Name)
+	if err != nil {
+		return "", fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+	hash := sha256.New()
+	err = gh.download(ctx, asset, io.MultiWriter(tmp, hash), nil)
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("download %s: %w", asset.Name, closeErr)
+	}
+	if err != nil {
+		return "", err
+	}
+	if checksums != nil {
+		if err := verifyDigest(checksums, asset.Name, hash.Sum(nil)); err != nil {
+			return "", err
+		}
+	}
+	if gh.cfg.verifier != nil {
+		if err := gh.verifyFile(ctx, release, asset.Name, tmp.Name()); err != nil {
+			return "", err
+		}
+	}
+	return tmp.Name(), nil
+}
+
+// downloadAsset returns the asset of release selected by opts.
+func (gh *GitHub) downloadAsset(release *Release,
+	opts DownloadOpts) (*Asset, error) {
+	if opts.AssetName != "" {
+		return release.Asset(opts.AssetName)
+	}
+	goos, goarch := opts.GOOS, opts.GOARCH
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	return gh.cfg.selectAsset(release, goos, goarch)
+}
@@ -0,0 +1,119 @@
+package release_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/marco-m/taschino/pkg/release"
+	"github.com/marco-m/taschino/pkg/release/releasetest"
+)
+
+// assetServer returns a server of the latest release of o/r, with the asset
+// "tool_linux_amd64" and its signature and, if withChecksums, a checksums
+// asset.
+func assetServer(t *testing.T, withChecksums bool) *releasetest.Server {
+	t.Helper()
+	srv := releasetest.NewServer()
+	t.Cleanup(srv.Close)
+	files := map[string][]byte{
+		"tool_linux_amd64":     []byte("binary"),
+		"tool_linux_amd64.sig": []byte("signature"),
+	}
+	if withChecksums {
+		sum := sha256.Sum256(files["tool_linux_amd64"])
+		files["checksums.txt"] = []byte(hex.EncodeToString(sum[:]) +
+			"  tool_linux_amd64\n")
+	}
+	r := release.Release{TagName: "v1.2.3"}
+	for name, body := range files {
+		srv.Handle("/download/"+name, releasetest.Response{Body: body})
+		r.Assets = append(r.Assets, release.Asset{Name: name,
+			BrowserDownloadURL: srv.URL + "/download/" + name})
+	}
+	srv.SetLatest("o", "r", r)
+	return srv
+}
+
+// verifier accepts the signature "signature" of the data "binary".
+var verifier = release.VerifierFunc(func(data io.Reader, sig []byte) error {
+	b, err := ioutil.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	if string(b) != "binary" || string(sig) != "signature" {
+		return errors.New("invalid signature")
+	}
+	return nil
+})
+
+func TestDownloadAndVerify(t *testing.T) {
+	tests := []struct {
+		name           string
+		withChecksums  bool
+		checksumsAsset string
+		verifier       release.Verifier
+		wantErr        error
+	}{
+		{"checksums", true, "", nil, nil},
+		{"checksums and signature", true, "", verifier, nil},
+		{"signature only", false, "", verifier, nil},
+		{"nothing to verify", false, "", nil, release.ErrNoAsset},
+		{"named checksums missing", false, "SHA256SUMS", verifier,
+			release.ErrNoAsset},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := assetServer(t, tc.withChecksums)
+
+			path, err := release.DownloadAndVerify(context.Background(),
+				release.DownloadOpts{
+					Owner: "o", Repo: "r", GOOS: "linux", GOARCH: "amd64",
+					ChecksumsAsset: tc.checksumsAsset,
+					Verifier:       tc.verifier,
+					Options:        []release.Option{release.WithBaseURL(srv.URL)},
+				})
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) || path != "" {
+					t.Fatalf("got path %q, error %v; want %v", path, err,
+						tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.Remove(path)
+			got, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != "binary" {
+				t.Errorf("got %q, want %q", got, "binary")
+			}
+		})
+	}
+}
+
+func TestDownloadAndVerifyBadSignatureWithoutChecksums(t *testing.T) {
+	srv := assetServer(t, false)
+	srv.Handle("/download/tool_linux_amd64.sig",
+		releasetest.Response{Body: []byte("forged")})
+
+	path, err := release.DownloadAndVerify(context.Background(),
+		release.DownloadOpts{
+			Owner: "o", Repo: "r", GOOS: "linux", GOARCH: "amd64",
+			Verifier: verifier,
+			Options:  []release.Option{release.WithBaseURL(srv.URL)},
+		})
+
+	if !errors.Is(err, release.ErrBadSignature) || path != "" {
+		t.Fatalf("got path %q, error %v; want ErrBadSignature", path, err)
+	}
+}
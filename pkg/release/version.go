@@ -0,0 +1,210 @@
+package release
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// normalize returns v with a leading "v", as required by package semver, so
+// that "1.2.3" is treated as "v1.2.3".
+func normalize(v string) string {
+	if strings.HasPrefix(v, "v") {
+		return v
+	}
+	return "v" + v
+}
+
+// SortVersions returns a copy of vs sorted in ascending semver order. The
+// leading "v" is optional. Strings that are not valid semver are placed at the
+// end, in their original order.
+func SortVersions(vs []string) []string {
+	sorted := make([]string, len(vs))
+	copy(sorted, vs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := normalize(sorted[i]), normalize(sorted[j])
+		aValid, bValid := semver.IsValid(a), semver.IsValid(b)
+		if aValid != bValid {
+			return aValid
+		}
+		return aValid && semver.Compare(a, b) < 0
+	})
+	return sorted
+}
+
+// StripPrefix returns tag without prefix, such as "1.2.3" for "release-1.2.3"
+// and prefix "release-", or tag unchanged if it does not start with prefix.
+// The result is a version to which the usual rules apply: the leading "v" is
+// optional, so that "app/v1.2.3" with prefix "app/" becomes "v1.2.3", and
+// build metadata, as in "1.2.3+build", is ignored by Compare.
+func StripPrefix(tag string, prefix string) string {
+	return strings.TrimPrefix(tag, prefix)
+}
+
+// IsNewer reports whether latestV is newer than currentV, that is whether
+// Compare(currentV, latestV) is -1, or the Comparator of WithComparator
+// returns -1.
+//
+// As a convenience for a fresh install, with no recorded version, an empty
+// currentV is older than any valid latestV: IsNewer("", latestV) is true,
+// while Compare("", latestV) fails. An empty or otherwise invalid latestV is
+// still an error.
+func IsNewer(currentV string, latestV string, opts ...Option) (bool, error) {
+	cmp := newConfig(opts).comparator
+	if currentV == "" {
+		if _, err := cmp.Compare(latestV, latestV); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	c, err := cmp.Compare(currentV, latestV)
+	if err != nil {
+		return false, err
+	}
+	return c < 0, nil
+}
+
+// IsDowngrade reports whether latestV is older than currentV, that is whether
+// Compare(currentV, latestV) is +1: for example, currentV is a development
+// build, or the release of currentV was withdrawn. Offering latestV as an
+// update would then be a downgrade.
+func IsDowngrade(currentV string, latestV string) (bool, error) {
+	c, err := Compare(currentV, latestV)
+	if err != nil {
+		return false, err
+	}
+	return c > 0, nil
+}
+
+// AtLeast reports whether currentV is greater than or equal to minimumV, for
+// example to refuse clients older than a supported floor. The leading "v" is
+// optional, as in Compare.
+func AtLeast(currentV string, minimumV string) (bool, error) {
+	cur, err := validVersion(currentV, "current")
+	if err != nil {
+		return false, err
+	}
+	minimum, err := validVersion(minimumV, "minimum")
+	if err != nil {
+		return false, err
+	}
+	return semver.Compare(cur, minimum) >= 0, nil
+}
+
+// Newest returns the highest of candidates by semver precedence, or by the
+// Comparator of WithComparator, as it appears in candidates. The leading "v"
+// is optional. Candidates that are not valid are skipped; if no candidate is
+// valid, the error reports how many were skipped.
+func Newest(candidates []string, opts ...Option) (string, error) {
+	newest, skipped := newest(newConfig(opts).comparator, "", candidates)
+	if newest == "" {
+		return "", fmt.Errorf("no valid version among %d candidates "+
+			"(%d skipped)", len(candidates), skipped)
+	}
+	return newest, nil
+}
+
+// NewestNewerThan returns the highest of candidates that is strictly newer
+// than current, and true; or "" and false if no candidate is newer. The
+// leading "v" is optional. Candidates that are not valid are skipped. It
+// returns an error only if current is not valid. As Newest, it uses the
+// Comparator of WithComparator, if any.
+func NewestNewerThan(current string, candidates []string,
+	opts ...Option) (string, bool, error) {
+	cmp := newConfig(opts).comparator
+	if _, err := cmp.Compare(current, current); err != nil {
+		return "", false, err
+	}
+	newest, _ := newest(cmp, current, candidates)
+	return newest, newest != "", nil
+}
+
+// newest returns the highest of candidates strictly greater than floor (valid
+// for cmp, or "" for no floor), or "" if there is none, and the number of
+// candidates that are not valid for cmp.
+func newest(cmp Comparator, floor string, candidates []string) (string, int) {
+	best, bestV := "", floor
+	skipped := 0
+	for _, c := range candidates {
+		if _, err := cmp.Compare(c, c); err != nil {
+			skipped++
+			continue
+		}
+		if bestV == "" {
+			best, bestV = c, c
+			continue
+		}
+		if d, _ := cmp.Compare(c, bestV); d > 0 {
+			best, bestV = c, c
+		}
+	}
+	return best, skipped
+}
+
+// CompareIgnoringPrerelease is like Compare, but ignores the prerelease (and,
+// as Compare, the build metadata) of a and b, comparing only major, minor and
+// patch: v1.2.3-rc.1 is the same as v1.2.3, and less than v1.2.4.
+func CompareIgnoringPrerelease(a string, b string) (int, error) {
+	x, err := validVersion(a, "first")
+	if err != nil {
+		return 0, err
+	}
+	y, err := validVersion(b, "second")
+	if err != nil {
+		return 0, err
+	}
+	return semver.Compare(core(semver.Canonical(x)), core(semver.Canonical(y))),
+		nil
+}
+
+// IsPromotion reports whether latestV is the stable release of the prerelease
+// currentV, such as v2.0.0 for v2.0.0-rc.3: both have the same major, minor
+// and patch, currentV has a prerelease and latestV does not. The leading "v" is
+// optional, as in Compare.
+func IsPromotion(currentV string, latestV string) (bool, error) {
+	cur, err := validVersion(currentV, "current")
+	if err != nil {
+		return false, err
+	}
+	latest, err := validVersion(latestV, "latest")
+	if err != nil {
+		return false, err
+	}
+	return semver.Prerelease(cur) != "" && semver.Prerelease(latest) == "" &&
+		core(semver.Canonical(cur)) == core(semver.Canonical(latest)), nil
+}
+
+// Canonicalize returns the canonical form of the semver v, such as "v1.2.0"
+// for "1.2", or an error if v is not a valid semver. The leading "v" is
+// optional, as in Compare, and build metadata is dropped, since it does not
+// affect precedence: Canonicalize(a) == Canonicalize(b) if and only if
+// Compare(a, b) is 0.
+func Canonicalize(v string) (string, error) {
+	n, err := validVersion(v, "the")
+	if err != nil {
+		return "", err
+	}
+	return semver.Canonical(n), nil
+}
+
+// MustCompare is like Compare but panics if curV or latestV is not a valid
+// semver. It simplifies comparing known-good versions, such as constants in
+// tests or in the initialization of global variables.
+func MustCompare(curV string, latestV string) int {
+	c, err := Compare(curV, latestV)
+	if err != nil {
+		panic("release: MustCompare: " + err.Error())
+	}
+	return c
+}
+
+// MustCanonicalize is like Canonicalize but panics if v is not a valid semver.
+func MustCanonicalize(v string) string {
+	c, err := Canonicalize(v)
+	if err != nil {
+		panic("release: MustCanonicalize: " + err.Error())
+	}
+	return c
+}
@@ -0,0 +1,63 @@
+package release_test
+
+import (
+	"testing"
+
+	"github.com/marco-m/taschino/pkg/release"
+)
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		latest  string
+		want    bool
+	}{
+		{"older", "v1.2.3", "v1.2.4", true},
+		{"equal", "v1.2.3", "1.2.3", false},
+		{"newer", "v1.3.0", "v1.2.4", false},
+		{"empty current", "", "v1.2.3", true},
+		{"empty current, prerelease", "", "v0.1.0-rc.1", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := release.IsNewer(tc.current, tc.latest)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("IsNewer(%q, %q): got %v, want %v", tc.current,
+					tc.latest, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsNewerInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		latest  string
+	}{
+		{"empty latest", "v1.2.3", ""},
+		{"both empty", "", ""},
+		{"empty current, invalid latest", "", "nightly"},
+		{"invalid current", "banana", "v1.2.3"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := release.IsNewer(tc.current, tc.latest); err == nil {
+				t.Errorf("IsNewer(%q, %q): got no error", tc.current,
+					tc.latest)
+			}
+		})
+	}
+}
+
+func TestCompareEmptyCurrentIsInvalid(t *testing.T) {
+	// Unlike IsNewer, Compare has no special case for a fresh install.
+	if _, err := release.Compare("", "v1.2.3"); err == nil {
+		t.Error("got no error")
+	}
+}
@@ -0,0 +1,196 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Update is a notification of Watch.
+type Update struct {
+	// Current is the version being watched for updates.
+	Current string
+	// Latest is the newer version found. Empty if Err is set.
+	Latest string
+	// Err is the error of a failed check, if any.
+	Err error
+}
+
+// Watch checks the latest release of the GitHub repository owner/repo right
+// away and then every interval, and sends an Update on the returned channel
+// when it finds a version newer than currentV. It notifies each newer version
+// only once. Failed checks are sent too, as an Update with Err set, so that
+// they are not silently dropped; the watch keeps going.
+//
+// With WithOnUpdate or WithWebhook, each newer version is also passed to the
+// callback, before being sent on the channel. A failed callback is sent as an
+// Update with Err set and does not stop the watch.
+//
+// An interval shorter than MinWatchInterval, zero or negative included, is
+// taken as MinWatchInterval, so that a misconfigured interval cannot make
+// the watch hammer the API.
+//
+// The caller must keep receiving from the channel. Watch stops, and closes the
+// channel, when ctx is canceled. To change the interval while watching, use
+// NewWatcher.
+func Watch(ctx context.Context, owner string, repo string, currentV string,
+	interval time.Duration, opts ...Option) <-chan Update {
+	gh := NewGitHub(owner, repo, opts...)
+	return newWatcher(ctx, gh, currentV, interval, gh.cfg).Updates()
+}
+
+// MinWatchInterval is the shortest interval between the checks of Watch.
+const MinWatchInterval = time.Second
+
+// WatchProvider is like Watch, for any Provider. Of opts, only those about
+// comparing versions and callbacks apply.
+func WatchProvider(ctx context.Context, p Provider, currentV string,
+	interval time.Duration, opts ...Option) <-chan Update {
+	return NewWatcher(ctx, p, currentV, interval, opts...).Updates()
+}
+
+// Watcher is a watch in progress, as started by Watch, whose interval can be
+// changed.
+type Watcher struct {
+	updates chan Update
+	// changed is signaled, without blocking, when interval changes.
+	changed  chan struct{}
+	mu       sync.Mutex
+	interval time.Duration
+}
+
+// NewWatcher starts watching p as WatchProvider and returns the Watcher. The
+// updates are sent on the channel returned by Updates.
+func NewWatcher(ctx context.Context, p Provider, currentV string,
+	interval time.Duration, opts ...Option) *Watcher {
+	return newWatcher(ctx, p, currentV, interval, newConfig(opts))
+}
+
+// Updates returns the channel of the updates. See Watch.
+func (w *Watcher) Updates() <-chan Update {
+	return w.updates
+}
+
+// SetInterval changes the interval between checks to d. The next check
+// happens d after the previous one, or right away if that is already past:
+// no check is skipped or repeated because of the change. It never blocks, so
+// it can be called from the goroutine receiving the updates. As for Watch, an
+// interval shorter than MinWatchInterval is taken as MinWatchInterval.
+func (w *Watcher) SetInterval(d time.Duration) {
+	w.mu.Lock()
+	w.interval = watchInterval(d)
+	w.mu.Unlock()
+	select {
+	case w.changed <- struct{}{}:
+	default:
+		// A change is already pending; it will read the new interval.
+	}
+}
+
+func (w *Watcher) currentInterval() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.interval
+}
+
+// newWatcher is NewWatcher, configured by cfg.
+func newWatcher(ctx context.Context, p Provider, currentV string,
+	interval time.Duration, cfg *config) *Watcher {
+	w := &Watcher{
+		updates:  make(chan Update),
+		changed:  make(chan struct{}, 1),
+		interval: watchInterval(interval),
+	}
+	go w.run(ctx, p, currentV, cfg)
+	return w
+}
+
+// watchInterval returns d, or MinWatchInterval if d is shorter.
+func watchInterval(d time.Duration) time.Duration {
+	if d < MinWatchInterval {
+		return MinWatchInterval
+	}
+	return d
+}
+
+func (w *Watcher) run(ctx context.Context, p Provider, currentV string,
+	cfg *config) {
+	defer close(w.updates)
+	send := func(update Update) bool {
+		select {
+		case w.updates <- update:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	notified := ""
+	// The first check is right away.
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	var checkedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.changed:
+			if !timer.Stop() {
+				// Fired but not received: drain it, the check is due anyway.
+				<-timer.C
+			}
+			wait := w.currentInterval() - time.Since(checkedAt)
+			if wait < 0 {
+				wait = 0
+			}
+			timer.Reset(wait)
+			continue
+		case <-timer.C:
+		}
+		checkedAt = time.Now()
+		update, ok := check(ctx, p, currentV, cfg)
+		if ok && !(update.Err == nil && update.Latest == notified) {
+			var callbackErr error
+			if update.Err == nil && cfg.onUpdate != nil {
+				if err := cfg.onUpdate(ctx, update); err != nil {
+					cfg.warn("update callback failed", "latest", update.Latest,
+						"error", err)
+					callbackErr = fmt.Errorf("update callback for %s: %w",
+						update.Latest, err)
+				}
+			}
+			if !send(update) {
+				return
+			}
+			if update.Err == nil {
+				notified = update.Latest
+			}
+			if callbackErr != nil &&
+				!send(Update{Current: currentV, Err: callbackErr}) {
+				return
+			}
+		}
+		timer.Reset(w.currentInterval())
+	}
+}
+
+// check queries p and returns the Update to notify, if any.
+func check(ctx context.Context, p Provider, currentV string,
+	cfg *config) (Update, bool) {
+	latest, err := p.Latest(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			// Canceled: not worth notifying.
+			return Update{}, false
+		}
+		return Update{Current: currentV, Err: err}, true
+	}
+	c, err := cfg.comparator.Compare(currentV, cfg.tagVersion(latest))
+	if err != nil {
+		return Update{Current: currentV, Err: err}, true
+	}
+	if c >= 0 {
+		return Update{}, false
+	}
+	return Update{Current: currentV, Latest: latest}, true
+}
@@ -0,0 +1,74 @@
+package release_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marco-m/taschino/pkg/release"
+)
+
+// countingProvider is a Provider answering tag and counting its calls.
+type countingProvider struct {
+	tag   string
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *countingProvider) Latest(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	return p.tag, nil
+}
+
+func (p *countingProvider) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+func TestWatchNonPositiveInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second, time.Nanosecond} {
+		t.Run(interval.String(), func(t *testing.T) {
+			p := &countingProvider{tag: "v1.0.0"}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			updates := release.WatchProvider(ctx, p, "v1.0.0", interval)
+			time.Sleep(200 * time.Millisecond)
+
+			// Only the first check, right away: the next one is
+			// MinWatchInterval later.
+			if n := p.Calls(); n != 1 {
+				t.Errorf("got %d checks, want 1", n)
+			}
+			cancel()
+			for range updates {
+			}
+		})
+	}
+}
+
+func TestWatcherSetNonPositiveInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		t.Run(interval.String(), func(t *testing.T) {
+			p := &countingProvider{tag: "v1.0.0"}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			w := release.NewWatcher(ctx, p, "v1.0.0", time.Hour)
+			time.Sleep(50 * time.Millisecond)
+
+			w.SetInterval(interval)
+			time.Sleep(200 * time.Millisecond)
+
+			if n := p.Calls(); n != 1 {
+				t.Errorf("got %d checks, want 1", n)
+			}
+			cancel()
+			for range w.Updates() {
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package release
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// webhookPayload is the JSON body POSTed by the callback of WithWebhook.
+type webhookPayload struct {
+	Current string `json:"current"`
+	Latest  string `json:"latest"`
+}
+
+// webhook returns an update callback POSTing the update as JSON to url.
+func webhook(cfg *config, url string) func(context.Context, Update) error {
+	return func(ctx context.Context, update Update) error {
+		body, err := json.Marshal(webhookPayload{
+			Current: update.Current,
+			Latest:  update.Latest,
+		})
+		if err != nil {
+			return fmt.Errorf("webhook: %w", err)
+		}
+		ctx, cancel := cfg.withTimeout(ctx)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url,
+			bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("create http request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", cfg.userAgent)
+		resp, err := cfg.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("http client Do: %w", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("webhook %s: unexpected status %s", url,
+				resp.Status)
+		}
+		return nil
+	}
+}